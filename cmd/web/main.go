@@ -8,12 +8,18 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	server "github.com/f1monkey/spellchecker-web"
+	"github.com/f1monkey/spellchecker-web/internal/cluster"
 	"github.com/f1monkey/spellchecker-web/internal/logger"
+	"github.com/f1monkey/spellchecker-web/internal/replication"
+	"github.com/f1monkey/spellchecker-web/internal/routes"
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/storage"
 )
 
 var GitCommit string = "dev"
@@ -37,7 +43,19 @@ func main() {
 		logger.New(GitCommit, os.Getenv("SPELLCHECKER_LOG_LEVEL")),
 	)
 
-	registry, err := initRegistry(ctx)
+	readOnly, err := initReadOnly()
+	if err != nil {
+		logger.FromContext(ctx).Error("init spellchecker error", "error", err)
+		os.Exit(1)
+	}
+
+	restoreLimit, err := initRestoreLimit()
+	if err != nil {
+		logger.FromContext(ctx).Error("init spellchecker error", "error", err)
+		os.Exit(1)
+	}
+
+	registry, err := initRegistry(ctx, readOnly, restoreLimit)
 	if err != nil {
 		logger.FromContext(ctx).Error("init spellchecker error", "error", err)
 		os.Exit(1)
@@ -49,9 +67,40 @@ func main() {
 		os.Exit(1)
 	}
 
-	defer registry.SaveAll(ctx)
+	if !readOnly {
+		defer registry.SaveAll(ctx)
+	}
+
+	routeOpts, err := initReplication(ctx, registry)
+	if err != nil {
+		logger.FromContext(ctx).Error("init replication error", "error", err)
+		os.Exit(1)
+	}
+
+	clusterOpts, err := initCluster(ctx, registry)
+	if err != nil {
+		logger.FromContext(ctx).Error("init cluster error", "error", err)
+		os.Exit(1)
+	}
+
+	if len(routeOpts) > 0 && len(clusterOpts) > 0 {
+		logger.FromContext(ctx).Error("replication and cluster modes cannot be combined")
+		os.Exit(1)
+	}
+
+	if readOnly && (len(routeOpts) > 0 || len(clusterOpts) > 0) {
+		logger.FromContext(ctx).Error("read-only mode cannot be combined with replication or cluster modes")
+		os.Exit(1)
+	}
+
+	routeOpts = append(routeOpts, clusterOpts...)
+	routeOpts = append(routeOpts, routes.WithRestoreLimit(restoreLimit))
+
+	if readOnly {
+		routeOpts = append(routeOpts, routes.WithReadOnly())
+	}
 
-	server := server.NewServer(ctx, registry, splitter)
+	server := server.NewServer(ctx, registry, splitter, routeOpts...)
 
 	addr := defaultServerAddr
 	if a := os.Getenv("SPELLCHECKER_HTTP_ADDR"); a != "" {
@@ -92,14 +141,68 @@ func main() {
 	}
 }
 
-func initRegistry(ctx context.Context) (*spellchecker.Registry, error) {
-	dir := os.Getenv("SPELLCHECKER_DIR")
-	if dir == "" {
-		return nil, fmt.Errorf("env SPELLCHECKER_DIR must be provided")
+// initReadOnly checks if the instance must never change the dictionaries
+func initReadOnly() (bool, error) {
+	readOnlyStr := os.Getenv("SPELLCHECKER_READ_ONLY")
+	if readOnlyStr == "" {
+		return false, nil
 	}
 
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("unable to create dir %s: %w", dir, err)
+	readOnly, err := strconv.ParseBool(readOnlyStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid SPELLCHECKER_READ_ONLY: %w", err)
+	}
+
+	return readOnly, nil
+}
+
+// initRestoreLimit returns the max size of a restored archive in bytes
+func initRestoreLimit() (int64, error) {
+	limitStr := os.Getenv("SPELLCHECKER_RESTORE_LIMIT_MB")
+	if limitStr == "" {
+		return spellchecker.DefaultRestoreLimit, nil
+	}
+
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("invalid SPELLCHECKER_RESTORE_LIMIT_MB: %q", limitStr)
+	}
+
+	return limit << 20, nil
+}
+
+func initRegistry(ctx context.Context, readOnly bool, restoreLimit int64) (*spellchecker.Registry, error) {
+	var (
+		dir  string
+		opts = []spellchecker.RegistryOption{spellchecker.WithRestoreLimit(restoreLimit)}
+	)
+
+	switch storageType := os.Getenv("SPELLCHECKER_STORAGE"); storageType {
+	case "", "local":
+		dir = os.Getenv("SPELLCHECKER_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("env SPELLCHECKER_DIR must be provided")
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("unable to create dir %s: %w", dir, err)
+		}
+	case "s3":
+		s3, err := storage.NewS3(storage.S3Config{
+			Endpoint:  os.Getenv("SPELLCHECKER_S3_ENDPOINT"),
+			Region:    os.Getenv("SPELLCHECKER_S3_REGION"),
+			Bucket:    os.Getenv("SPELLCHECKER_S3_BUCKET"),
+			Prefix:    os.Getenv("SPELLCHECKER_S3_PREFIX"),
+			AccessKey: os.Getenv("SPELLCHECKER_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("SPELLCHECKER_S3_SECRET_KEY"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3 storage config: %w", err)
+		}
+
+		opts = append(opts, spellchecker.WithStorage(s3))
+	default:
+		return nil, fmt.Errorf("invalid SPELLCHECKER_STORAGE: %q", storageType)
 	}
 
 	var saveInterval time.Duration
@@ -114,16 +217,154 @@ func initRegistry(ctx context.Context) (*spellchecker.Registry, error) {
 		saveInterval = i
 	}
 
-	result, err := spellchecker.NewRegistry(ctx, dir)
+	var versions int
+
+	versionsStr := os.Getenv("SPELLCHECKER_VERSIONS")
+	if versionsStr != "" {
+		v, err := strconv.Atoi(versionsStr)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid SPELLCHECKER_VERSIONS: %q", versionsStr)
+		}
+
+		versions = v
+	}
+
+	opts = append(opts, spellchecker.WithVersions(versions))
+
+	if workersStr := os.Getenv("SPELLCHECKER_LOAD_WORKERS"); workersStr != "" {
+		workers, err := strconv.Atoi(workersStr)
+		if err != nil || workers < 1 {
+			return nil, fmt.Errorf("invalid SPELLCHECKER_LOAD_WORKERS: %q", workersStr)
+		}
+
+		opts = append(opts, spellchecker.WithLoadWorkers(workers))
+	}
+
+	if compressionStr := os.Getenv("SPELLCHECKER_COMPRESSION"); compressionStr != "" {
+		compression, err := spellchecker.ParseCompression(compressionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPELLCHECKER_COMPRESSION: %w", err)
+		}
+
+		opts = append(opts, spellchecker.WithCompression(compression))
+	}
+
+	if walDir := os.Getenv("SPELLCHECKER_WAL_DIR"); walDir != "" {
+		if readOnly {
+			return nil, fmt.Errorf("SPELLCHECKER_WAL_DIR cannot be used in the read-only mode")
+		}
+
+		walSync := spellchecker.WALSyncAlways
+
+		if walSyncStr := os.Getenv("SPELLCHECKER_WAL_SYNC"); walSyncStr != "" {
+			var err error
+
+			walSync, err = spellchecker.ParseWALSync(walSyncStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SPELLCHECKER_WAL_SYNC: %w", err)
+			}
+		}
+
+		opts = append(opts, spellchecker.WithWAL(walDir, walSync))
+	}
+
+	if lazyStr := os.Getenv("SPELLCHECKER_LAZY_LOADING"); lazyStr != "" {
+		lazy, err := strconv.ParseBool(lazyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPELLCHECKER_LAZY_LOADING: %w", err)
+		}
+
+		var budget int64
+
+		budgetStr := os.Getenv("SPELLCHECKER_MEMORY_BUDGET_MB")
+		if budgetStr != "" {
+			b, err := strconv.ParseInt(budgetStr, 10, 64)
+			if err != nil || b < 0 {
+				return nil, fmt.Errorf("invalid SPELLCHECKER_MEMORY_BUDGET_MB: %q", budgetStr)
+			}
+
+			budget = b << 20
+		}
+
+		if lazy {
+			opts = append(opts, spellchecker.WithLazyLoading(budget))
+		}
+	}
+
+	if readOnly {
+		opts = append(opts, spellchecker.WithReadOnly())
+	}
+
+	result, err := spellchecker.NewRegistry(ctx, dir, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	result.AutoSave(ctx, saveInterval)
+	if !readOnly {
+		result.AutoSave(ctx, saveInterval)
+	}
+
+	if reloadStr := os.Getenv("SPELLCHECKER_RELOAD_INTERVAL"); reloadStr != "" {
+		reloadInterval, err := time.ParseDuration(reloadStr)
+		if err != nil || reloadInterval < 0 {
+			return nil, fmt.Errorf("invalid SPELLCHECKER_RELOAD_INTERVAL: %q", reloadStr)
+		}
+
+		result.Watch(ctx, reloadInterval)
+	}
 
 	return result, nil
 }
 
+const defaultReplicationInterval = 10 * time.Second
+
+// initReplication starts pulling the dictionaries from the leader if the instance is a follower
+func initReplication(ctx context.Context, registry *spellchecker.Registry) ([]routes.Option, error) {
+	leader := os.Getenv("SPELLCHECKER_REPLICATION_LEADER")
+	if leader == "" {
+		return nil, nil
+	}
+
+	interval := defaultReplicationInterval
+
+	if intervalStr := os.Getenv("SPELLCHECKER_REPLICATION_INTERVAL"); intervalStr != "" {
+		i, err := time.ParseDuration(intervalStr)
+		if err != nil || i <= 0 {
+			return nil, fmt.Errorf("invalid SPELLCHECKER_REPLICATION_INTERVAL: %q", intervalStr)
+		}
+
+		interval = i
+	}
+
+	follower, err := replication.NewFollower(ctx, leader, registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPELLCHECKER_REPLICATION_LEADER: %w", err)
+	}
+
+	go follower.Run(ctx, interval)
+
+	return []routes.Option{routes.WithFollower(follower)}, nil
+}
+
+const clusterRebalanceRetry = 10 * time.Second
+
+// initCluster proxies the requests to the peers owning the dictionaries and moves the dictionaries owned by other peers
+func initCluster(ctx context.Context, registry *spellchecker.Registry) ([]routes.Option, error) {
+	peers := os.Getenv("SPELLCHECKER_CLUSTER_PEERS")
+	if peers == "" {
+		return nil, nil
+	}
+
+	c, err := cluster.New(ctx, os.Getenv("SPELLCHECKER_CLUSTER_SELF"), strings.Split(peers, ","), registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster config: %w", err)
+	}
+
+	go c.RunRebalance(ctx, clusterRebalanceRetry)
+
+	return []routes.Option{routes.WithCluster(c)}, nil
+}
+
 var defaultRegexp = regexp.MustCompile(`['\pL]+`)
 
 func initWordSpliter() (*regexp.Regexp, error) {
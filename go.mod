@@ -3,8 +3,10 @@ module github.com/f1monkey/spellchecker-web
 go 1.24
 
 require (
+	github.com/agnivade/levenshtein v1.2.1
 	github.com/f1monkey/spellchecker v1.2.0
 	github.com/go-chi/chi/v5 v5.2.2
+	github.com/klauspost/compress v1.18.0
 	github.com/stretchr/testify v1.8.4
 	github.com/swaggest/openapi-go v0.2.59
 	github.com/swaggest/rest v0.2.75
@@ -13,7 +15,6 @@ require (
 )
 
 require (
-	github.com/agnivade/levenshtein v1.2.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/f1monkey/bitmap v1.4.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
@@ -0,0 +1,387 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/logger"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// forwardedHeader marks the requests proxied by a peer, they are always served locally to avoid loops
+	forwardedHeader = "X-Spellchecker-Forwarded-By"
+
+	dictionaryPath = "/v1/_cluster/dictionaries/"
+	aliasPath      = "/v1/aliases/"
+
+	// maxRebalanceAttempts limits the retries of RunRebalance, a dictionary kept because of local aliases would be sent forever
+	maxRebalanceAttempts = 30
+)
+
+type node interface {
+	ReplicationState() (spellchecker.ReplicationState, error)
+	WriteDictionary(code string, w io.Writer) error
+	Delete(code string, cascade bool) error
+	DeleteAlias(alias string) error
+}
+
+// RebalanceResult lists the dictionaries and the aliases moved to the peers owning them
+type RebalanceResult struct {
+	Dictionaries []string
+	Aliases      []string
+	Errors       []string // the names failed to move are kept locally and moved on the next rebalance
+}
+
+// Cluster routes the requests to the dictionaries and the aliases to the peers owning them
+type Cluster struct {
+	self     string
+	peers    []string
+	ring     *Ring
+	registry node
+	client   *http.Client
+	proxies  map[string]*httputil.ReverseProxy
+	log      *slog.Logger
+
+	rebalanceMu sync.Mutex
+}
+
+// New creates the cluster of the static list of peers (base URLs), self is the URL of this instance in the list
+func New(ctx context.Context, self string, peers []string, registry node) (*Cluster, error) {
+	result := &Cluster{
+		self:     strings.TrimSuffix(self, "/"),
+		registry: registry,
+		client:   &http.Client{},
+		proxies:  make(map[string]*httputil.ReverseProxy),
+		log:      logger.FromContext(ctx),
+	}
+
+	for _, peer := range peers {
+		peer = strings.TrimSuffix(strings.TrimSpace(peer), "/")
+		if peer == "" || slices.Contains(result.peers, peer) {
+			continue
+		}
+
+		u, err := url.Parse(peer)
+		if err != nil {
+			return nil, err
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("peer URL must be absolute: %q", peer)
+		}
+
+		result.peers = append(result.peers, peer)
+		result.proxies[peer] = result.newProxy(u)
+	}
+
+	if !slices.Contains(result.peers, result.self) {
+		return nil, fmt.Errorf("self %q is not in the peer list", self)
+	}
+
+	result.ring = NewRing(result.peers)
+
+	return result, nil
+}
+
+func (c *Cluster) Self() string {
+	return c.self
+}
+
+func (c *Cluster) Peers() []string {
+	return slices.Clone(c.peers)
+}
+
+// Owner returns the peer owning the dictionary or the alias
+func (c *Cluster) Owner(name string) string {
+	return c.ring.Owner(name)
+}
+
+// Proxy is the middleware of the dictionary and alias routes: a request is proxied to the owner of the name
+// (the first segment of the route path). Names starting with "_" (e.g. /_stats) are served locally.
+func (c *Cluster) Proxy(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := routeName(r)
+		if name == "" || strings.HasPrefix(name, "_") || r.Header.Get(forwardedHeader) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		owner := c.ring.Owner(name)
+		if owner == c.self {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c.proxies[owner].ServeHTTP(w, r)
+	})
+}
+
+func (c *Cluster) newProxy(target *url.URL) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.SetXForwarded()
+			r.Out.Header.Set(forwardedHeader, c.self)
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			c.log.Error("cluster: proxy error", "peer", target.String(), "path", r.URL.Path, "error", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+}
+
+// routeName returns the first segment of the path relative to the mounted routes (the code or the alias)
+func routeName(r *http.Request) string {
+	p := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		p = rctx.RoutePath
+	}
+
+	name, _, _ := strings.Cut(strings.TrimPrefix(p, "/"), "/")
+
+	if r.URL.RawPath != "" {
+		if unescaped, err := url.PathUnescape(name); err == nil {
+			name = unescaped
+		}
+	}
+
+	return name
+}
+
+// Misplaced returns the local dictionaries and aliases owned by other peers, they are moved by Rebalance
+func (c *Cluster) Misplaced() ([]string, error) {
+	state, err := c.registry.ReplicationState()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+
+	for _, name := range slices.Sorted(maps.Keys(state.Dictionaries)) {
+		if c.ring.Owner(name) != c.self {
+			result = append(result, name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(state.Metadata.Aliases)) {
+		if c.ring.Owner(name) != c.self {
+			result = append(result, name)
+		}
+	}
+
+	return result, nil
+}
+
+// RunRebalance rebalances the dictionaries on startup, retrying while some of them fail to move (e.g. the peers are not started yet)
+func (c *Cluster) RunRebalance(ctx context.Context, retry time.Duration) {
+	ticker := time.NewTicker(retry)
+	defer ticker.Stop()
+
+	for attempt := 1; attempt <= maxRebalanceAttempts; attempt++ {
+		result, err := c.Rebalance(ctx)
+		if err != nil {
+			c.log.Error("cluster: rebalance error", "error", err)
+		} else if len(result.Errors) > 0 {
+			c.log.Warn("cluster: rebalance incomplete", "moved", len(result.Dictionaries), "errors", result.Errors)
+		} else {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebalance moves the local dictionaries and aliases owned by other peers to their owners.
+// The dictionaries are moved first, then the aliases, the local copies are deleted after that.
+// A dictionary is kept if some local aliases still point to it.
+func (c *Cluster) Rebalance(ctx context.Context) (RebalanceResult, error) {
+	c.rebalanceMu.Lock()
+	defer c.rebalanceMu.Unlock()
+
+	state, err := c.registry.ReplicationState()
+	if err != nil {
+		return RebalanceResult{}, err
+	}
+
+	var result RebalanceResult
+
+	for _, code := range slices.Sorted(maps.Keys(state.Dictionaries)) {
+		owner := c.ring.Owner(code)
+		if owner == c.self {
+			continue
+		}
+
+		if err := c.sendDictionary(ctx, owner, code); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("dictionary %q: %s", code, err))
+			continue
+		}
+
+		result.Dictionaries = append(result.Dictionaries, code)
+		c.log.Info("cluster: dictionary moved", "dictionary", code, "peer", owner)
+	}
+
+	result.Aliases, result.Errors = c.moveAliases(ctx, state.Metadata, result.Errors)
+
+	for _, code := range result.Dictionaries {
+		if err := c.registry.Delete(code, false); err != nil && !errors.Is(err, spellchecker.ErrNotFound) {
+			result.Errors = append(result.Errors, fmt.Sprintf("dictionary %q: %s, the local copy is kept", code, err))
+		}
+	}
+
+	return result, nil
+}
+
+// moveAliases sends the aliases owned by other peers to their owners and deletes them locally.
+// An alias pointing to another alias is sent after that alias, so the aliases are retried while there is progress.
+func (c *Cluster) moveAliases(ctx context.Context, metadata spellchecker.Metadata, errs []string) ([]string, []string) {
+	var pending []string
+
+	for _, alias := range slices.Sorted(maps.Keys(metadata.Aliases)) {
+		if c.ring.Owner(alias) != c.self {
+			pending = append(pending, alias)
+		}
+	}
+
+	var (
+		moved   []string
+		lastErr = make(map[string]error)
+	)
+
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+
+		rest := pending[:0]
+		for _, alias := range pending {
+			if err := c.sendAlias(ctx, c.ring.Owner(alias), alias, metadata); err != nil {
+				lastErr[alias] = err
+				rest = append(rest, alias)
+
+				continue
+			}
+
+			moved = append(moved, alias)
+			progress = true
+		}
+
+		pending = rest
+	}
+
+	for _, alias := range pending {
+		errs = append(errs, fmt.Sprintf("alias %q: %s", alias, lastErr[alias]))
+	}
+
+	// local aliases pointing to the moved ones are deleted first
+	deleted := make(map[string]bool, len(moved))
+
+	for progress := true; progress; {
+		progress = false
+
+		for _, alias := range moved {
+			if deleted[alias] {
+				continue
+			}
+
+			err := c.registry.DeleteAlias(alias)
+			if errors.Is(err, spellchecker.ErrHasAliases) {
+				continue
+			} else if err != nil && !errors.Is(err, spellchecker.ErrAliasNotFound) {
+				errs = append(errs, fmt.Sprintf("alias %q: %s", alias, err))
+			}
+
+			deleted[alias] = true
+			progress = true
+		}
+	}
+
+	for _, alias := range moved {
+		if !deleted[alias] {
+			errs = append(errs, fmt.Sprintf("alias %q: local aliases still point to it, the local copy is kept", alias))
+		}
+	}
+
+	slices.Sort(moved)
+
+	return moved, errs
+}
+
+// sendDictionary uploads the file of the dictionary to the peer
+func (c *Cluster) sendDictionary(ctx context.Context, peer string, code string) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(c.registry.WriteDictionary(code, pw))
+	}()
+	defer pr.Close()
+
+	return c.send(ctx, http.MethodPut, peer+dictionaryPath+url.PathEscape(code), "application/octet-stream", pr)
+}
+
+// sendAlias sets the alias on the peer with the same targets
+func (c *Cluster) sendAlias(ctx context.Context, peer string, alias string, metadata spellchecker.Metadata) error {
+	type target struct {
+		Dictionary string `json:"dictionary"`
+		Weight     uint   `json:"weight"`
+	}
+
+	var body struct {
+		Dictionary string   `json:"dictionary,omitempty"`
+		Targets    []target `json:"targets,omitempty"`
+	}
+
+	if split, ok := metadata.Splits[alias]; ok {
+		for _, t := range split {
+			body.Targets = append(body.Targets, target{Dictionary: t.Dictionary, Weight: t.Weight})
+		}
+	} else {
+		body.Dictionary = metadata.Aliases[alias]
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, http.MethodPut, peer+aliasPath+url.PathEscape(alias), "application/json", bytes.NewReader(data))
+}
+
+func (c *Cluster) send(ctx context.Context, method string, target string, contentType string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(forwardedHeader, c.self)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type testPeer struct {
+	server   *httptest.Server
+	registry *spellchecker.Registry
+	handler  http.Handler
+}
+
+// newTestPeers starts the peers serving the dictionary and alias routes (just the names of the dictionaries) behind the proxy
+// and the endpoints used by Rebalance
+func newTestPeers(t *testing.T, n int) ([]*testPeer, []string) {
+	peers := make([]*testPeer, n)
+	urls := make([]string, n)
+
+	for i := range peers {
+		p := &testPeer{}
+		p.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p.handler.ServeHTTP(w, r)
+		}))
+		t.Cleanup(p.server.Close)
+
+		registry, err := spellchecker.NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		p.registry = registry
+		peers[i] = p
+		urls[i] = "http://" + p.server.Listener.Addr().String()
+	}
+
+	for i, p := range peers {
+		c, err := New(context.Background(), urls[i], urls, p.registry)
+		require.NoError(t, err)
+
+		r := chi.NewRouter()
+		r.Route("/v1/dictionaries", func(r chi.Router) {
+			r.Use(c.Proxy)
+			r.Get("/{code}", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprintf(w, "%s %s", urls[i], chi.URLParam(r, "code"))
+			})
+		})
+		r.Put("/v1/_cluster/dictionaries/{code}", func(w http.ResponseWriter, r *http.Request) {
+			if err := p.registry.ReplaceDictionary(chi.URLParam(r, "code"), r.Body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+		})
+		r.Put("/v1/aliases/{alias}", func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Dictionary string `json:"dictionary"`
+			}
+
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			if err := p.registry.SetAlias(chi.URLParam(r, "alias"), body.Dictionary); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			}
+		})
+
+		p.handler = r
+		p.server.Start()
+	}
+
+	return peers, urls
+}
+
+func Test_Cluster_Proxy(t *testing.T) {
+	t.Parallel()
+
+	peers, urls := newTestPeers(t, 3)
+
+	c, err := New(context.Background(), urls[0], urls, peers[0].registry)
+	require.NoError(t, err)
+
+	for _, name := range []string{"en", "de", "fr", "ru", "shop:en"} {
+		for _, p := range peers {
+			resp, err := http.Get(p.server.URL + "/v1/dictionaries/" + name)
+			require.NoError(t, err)
+
+			var body [256]byte
+			n, _ := resp.Body.Read(body[:])
+			resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, c.Owner(name)+" "+name, string(body[:n]))
+		}
+	}
+}
+
+func Test_Cluster_Rebalance(t *testing.T) {
+	t.Parallel()
+
+	peers, urls := newTestPeers(t, 3)
+	local := peers[0].registry
+
+	c, err := New(context.Background(), urls[0], urls, local)
+	require.NoError(t, err)
+
+	codes := []string{"en", "de", "fr", "ru", "es", "it", "shop:en"}
+	for _, code := range codes {
+		_, err := local.Add(code, spellchecker.Options{Alphabet: "abc"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, local.SetAlias("shop:main", "shop:en"))
+
+	misplaced, err := c.Misplaced()
+	require.NoError(t, err)
+	require.NotEmpty(t, misplaced)
+
+	result, err := c.Rebalance(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+
+	for _, code := range codes {
+		owner := c.Owner(code)
+
+		for i, p := range peers {
+			_, err := p.registry.GetItem(code)
+			if urls[i] == owner {
+				require.NoError(t, err, code)
+			} else {
+				require.ErrorIs(t, err, spellchecker.ErrNotFound, code)
+			}
+		}
+	}
+
+	for i, p := range peers {
+		_, err := p.registry.GetCodeByAlias("shop:main")
+		if urls[i] == c.Owner("shop:main") {
+			require.NoError(t, err)
+		} else {
+			require.True(t, errors.Is(err, spellchecker.ErrAliasNotFound))
+		}
+	}
+
+	misplaced, err = c.Misplaced()
+	require.NoError(t, err)
+	require.Empty(t, misplaced)
+}
+
+func Test_Cluster_Rebalance_PeerDown(t *testing.T) {
+	t.Parallel()
+
+	peers, urls := newTestPeers(t, 2)
+	local := peers[0].registry
+
+	c, err := New(context.Background(), urls[0], urls, local)
+	require.NoError(t, err)
+
+	peers[1].server.Close()
+
+	var moved string
+	for i := 0; moved == ""; i++ {
+		if code := fmt.Sprintf("dict%d", i); c.Owner(code) == urls[1] {
+			moved = code
+		}
+	}
+
+	_, err = local.Add(moved, spellchecker.Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	result, err := c.Rebalance(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	require.Empty(t, result.Dictionaries)
+
+	// the dictionary is kept until it is moved
+	_, err = local.GetItem(moved)
+	require.NoError(t, err)
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		self    string
+		peers   []string
+		wantErr bool
+	}{
+		{name: "ok", self: "http://a:8011/", peers: []string{"http://a:8011", " http://b:8011 ", "http://a:8011"}},
+		{name: "self not in peers", self: "http://c:8011", peers: []string{"http://a:8011", "http://b:8011"}, wantErr: true},
+		{name: "relative peer", self: "http://a:8011", peers: []string{"http://a:8011", "b:8011"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := New(context.Background(), tt.self, tt.peers, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []string{"http://a:8011", "http://b:8011"}, c.Peers())
+			require.Equal(t, "http://a:8011", c.Self())
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// virtualNodes is the number of points of each peer on the ring, more points spread the names more evenly
+const virtualNodes = 128
+
+// Ring assigns names (dictionary codes and aliases) to peers by consistent hashing,
+// so adding or removing a peer moves only the names of that peer.
+type Ring struct {
+	points []point
+}
+
+type point struct {
+	hash uint64
+	peer string
+}
+
+func NewRing(peers []string) *Ring {
+	result := &Ring{points: make([]point, 0, len(peers)*virtualNodes)}
+
+	for _, peer := range peers {
+		for i := range virtualNodes {
+			result.points = append(result.points, point{hash: hash(peer + "#" + strconv.Itoa(i)), peer: peer})
+		}
+	}
+
+	slices.SortFunc(result.points, func(a, b point) int {
+		if a.hash != b.hash {
+			return cmp.Compare(a.hash, b.hash)
+		}
+
+		return strings.Compare(a.peer, b.peer)
+	})
+
+	return result
+}
+
+// Owner returns the peer owning the name. Only the hash tag of the name is hashed (see HashTag).
+func (r *Ring) Owner(name string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	h := hash(HashTag(name))
+
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		return cmp.Compare(p.hash, h)
+	})
+
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.points[i].peer
+}
+
+// HashTag returns the part of the name before the first colon (the whole name if there is no colon),
+// so "shop:en" and "shop:main" are owned by the same peer.
+func HashTag(name string) string {
+	if tag, _, ok := strings.Cut(name, ":"); ok && tag != "" {
+		return tag
+	}
+
+	return name
+}
+
+// hash is FNV-1a with the MurmurHash3 finalizer: FNV alone places similar strings (e.g. peer URLs differing in the port) close to each other
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return x
+}
@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Ring_Owner(t *testing.T) {
+	t.Parallel()
+
+	peers := []string{"http://localhost:8011", "http://localhost:8012", "http://localhost:8013"}
+
+	t.Run("stable", func(t *testing.T) {
+		t.Parallel()
+
+		r1 := NewRing(peers)
+		r2 := NewRing([]string{peers[2], peers[0], peers[1]})
+
+		for i := range 100 {
+			name := fmt.Sprintf("dict%d", i)
+			require.Equal(t, r1.Owner(name), r2.Owner(name))
+		}
+	})
+
+	t.Run("spread", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRing(peers)
+
+		counts := make(map[string]int)
+		for i := range 3000 {
+			counts[r.Owner(fmt.Sprintf("dict%d", i))]++
+		}
+
+		for _, p := range peers {
+			require.Greater(t, counts[p], 500, p)
+		}
+	})
+
+	t.Run("peer added", func(t *testing.T) {
+		t.Parallel()
+
+		before := NewRing(peers)
+		after := NewRing(append(peers, "http://localhost:8014"))
+
+		for i := range 1000 {
+			name := fmt.Sprintf("dict%d", i)
+
+			// a name either stays or moves to the new peer
+			if owner := after.Owner(name); owner != before.Owner(name) {
+				require.Equal(t, "http://localhost:8014", owner)
+			}
+		}
+	})
+
+	t.Run("hash tag", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRing(peers)
+
+		for i := range 100 {
+			tag := fmt.Sprintf("shop%d", i)
+			require.Equal(t, r.Owner(tag+":en"), r.Owner(tag+":main"))
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, NewRing(nil).Owner("en"))
+	})
+}
+
+func Test_HashTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "en", want: "en"},
+		{name: "shop:en", want: "shop"},
+		{name: "shop:en:v2", want: "shop"},
+		{name: ":en", want: ":en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, HashTag(tt.name))
+		})
+	}
+}
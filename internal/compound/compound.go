@@ -0,0 +1,211 @@
+package compound
+
+import (
+	"unicode"
+)
+
+const (
+	defaultMinPartLength = 3
+
+	// MaxLength is the max length of a word in runes to be split, longer ones are never compounds
+	// and would take too long to try every split
+	MaxLength = 64
+)
+
+type Options struct {
+	MinPartLength    uint     `json:"minPartLength,omitempty"`
+	LinkingMorphemes []string `json:"linkingMorphemes,omitempty"`
+}
+
+// Part is a part of a compound word. Start and End are rune offsets inside the word.
+type Part struct {
+	Text  string
+	Start int
+	End   int
+	Known bool
+}
+
+type splitter struct {
+	runes  []rune
+	minLen int
+	links  [][]rune
+	known  func(string) bool
+	failed map[span]struct{} // ranges which cannot be decomposed
+}
+
+type span struct {
+	from         int
+	to           int
+	trailingLink bool
+}
+
+func newSplitter(word string, known func(string) bool, opts Options) *splitter {
+	s := &splitter{
+		runes:  []rune(word),
+		minLen: int(opts.MinPartLength),
+		known:  known,
+		failed: make(map[span]struct{}),
+	}
+
+	if s.minLen <= 0 {
+		s.minLen = defaultMinPartLength
+	}
+
+	for _, m := range opts.LinkingMorphemes {
+		if m != "" {
+			s.links = append(s.links, []rune(m))
+		}
+	}
+
+	return s
+}
+
+// Split decomposes the word into at least two parts known by the provided function.
+// Parts are also looked up with the first letter case toggled (e.g. "Dampf" inside "Donaudampfschiff").
+// Linking morphemes are allowed between parts, they are not returned as parts.
+func Split(word string, known func(string) bool, opts Options) ([]Part, bool) {
+	s := newSplitter(word, known, opts)
+	if len(s.runes) > MaxLength {
+		return nil, false
+	}
+
+	parts, ok := s.decompose(0, len(s.runes), false)
+	if !ok || len(parts) < 2 {
+		return nil, false
+	}
+
+	return parts, true
+}
+
+// SplitUnknown decomposes the word into known parts and exactly one unknown part,
+// which is likely a misspelled one. The shortest possible unknown part is chosen.
+func SplitUnknown(word string, known func(string) bool, opts Options) ([]Part, int, bool) {
+	s := newSplitter(word, known, opts)
+	n := len(s.runes)
+	if n > MaxLength {
+		return nil, 0, false
+	}
+
+	for l := s.minLen; l < n; l++ {
+		for a := 0; a+l <= n; a++ {
+			b := a + l
+
+			var prefix, suffix []Part
+
+			if a > 0 {
+				p, ok := s.decompose(0, a, true)
+				if !ok {
+					continue
+				}
+				prefix = p
+			}
+
+			if b < n {
+				p, ok := s.decompose(b, n, false)
+				if !ok {
+					continue
+				}
+				suffix = p
+			}
+
+			result := make([]Part, 0, len(prefix)+len(suffix)+1)
+			result = append(result, prefix...)
+			result = append(result, Part{Text: string(s.runes[a:b]), Start: a, End: b})
+			result = append(result, suffix...)
+
+			return result, len(prefix), true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// decompose splits runes[from:to] into known parts preferring the longest ones.
+// If trailingLink is true, a linking morpheme is allowed after the last part.
+func (s *splitter) decompose(from int, to int, trailingLink bool) ([]Part, bool) {
+	key := span{from: from, to: to, trailingLink: trailingLink}
+	if _, ok := s.failed[key]; ok {
+		return nil, false
+	}
+
+	for j := to; j >= from+s.minLen; j-- {
+		text := string(s.runes[from:j])
+		if !s.isKnown(text) {
+			continue
+		}
+
+		part := Part{Text: text, Start: from, End: j, Known: true}
+		if j == to {
+			return []Part{part}, true
+		}
+
+		if rest, ok := s.decompose(j, to, trailingLink); ok {
+			return append([]Part{part}, rest...), true
+		}
+
+		for _, m := range s.links {
+			k := j + len(m)
+			if k > to || string(s.runes[j:k]) != string(m) {
+				continue
+			}
+
+			if k == to {
+				if trailingLink {
+					return []Part{part}, true
+				}
+
+				continue
+			}
+
+			if rest, ok := s.decompose(k, to, trailingLink); ok {
+				return append([]Part{part}, rest...), true
+			}
+		}
+	}
+
+	s.failed[key] = struct{}{}
+
+	return nil, false
+}
+
+func (s *splitter) isKnown(text string) bool {
+	if s.known(text) {
+		return true
+	}
+
+	toggled := toggleFirst(text)
+
+	return toggled != text && s.known(toggled)
+}
+
+// MatchCase changes the case of the first letter of the word to the one of the part,
+// so a suggestion for "dampf" inside "Donaudampfschiff" is returned as "dampf", not "Dampf".
+func MatchCase(word string, part string) string {
+	w, p := []rune(word), []rune(part)
+	if len(w) == 0 || len(p) == 0 {
+		return word
+	}
+
+	if unicode.IsUpper(p[0]) {
+		w[0] = unicode.ToUpper(w[0])
+	} else {
+		w[0] = unicode.ToLower(w[0])
+	}
+
+	return string(w)
+}
+
+func toggleFirst(text string) string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return text
+	}
+
+	if unicode.IsUpper(runes[0]) {
+		runes[0] = unicode.ToLower(runes[0])
+	} else {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,134 @@
+package compound
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func knownWords(words ...string) func(string) bool {
+	m := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		m[w] = struct{}{}
+	}
+
+	return func(s string) bool {
+		_, ok := m[s]
+		return ok
+	}
+}
+
+func partTexts(parts []Part) []string {
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		result = append(result, p.Text)
+	}
+
+	return result
+}
+
+func Test_Split(t *testing.T) {
+	t.Parallel()
+
+	known := knownWords("Donau", "Dampf", "Schiff", "Fahrt", "Arbeit", "Amt", "Haus", "Tür")
+	opts := Options{MinPartLength: 3, LinkingMorphemes: []string{"s", "es"}}
+
+	tests := []struct {
+		name   string
+		word   string
+		want   []string
+		wantOk bool
+	}{
+		{name: "compound", word: "Donaudampfschifffahrt", want: []string{"Donau", "dampf", "schiff", "fahrt"}, wantOk: true},
+		{name: "linking morpheme", word: "Arbeitsamt", want: []string{"Arbeit", "amt"}, wantOk: true},
+		{name: "single known word", word: "Haus", wantOk: false},
+		{name: "unknown part", word: "Haustxr", wantOk: false},
+		{name: "short parts", word: "Haustür", want: []string{"Haus", "tür"}, wantOk: true},
+		{name: "trailing morpheme", word: "Arbeits", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parts, ok := Split(tt.word, known, opts)
+			require.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				require.Equal(t, tt.want, partTexts(parts))
+			}
+		})
+	}
+
+	t.Run("min part length", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := Split("Haustür", known, Options{MinPartLength: 4})
+		require.False(t, ok)
+	})
+
+	t.Run("max length", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := Split(strings.Repeat("Haus", MaxLength/4), known, opts)
+		require.True(t, ok)
+
+		_, ok = Split(strings.Repeat("Haus", MaxLength/4+1), known, opts)
+		require.False(t, ok)
+	})
+}
+
+func Test_SplitUnknown(t *testing.T) {
+	t.Parallel()
+
+	known := knownWords("Donau", "Dampf", "Schiff", "Fahrt")
+	opts := Options{MinPartLength: 3}
+
+	t.Run("misspelled part", func(t *testing.T) {
+		t.Parallel()
+
+		parts, idx, ok := SplitUnknown("Donaudampfschiffahrt", known, opts)
+		require.True(t, ok)
+		require.Equal(t, []string{"Donau", "dampf", "schiff", "ahrt"}, partTexts(parts))
+		require.Equal(t, 3, idx)
+		require.False(t, parts[idx].Known)
+		require.Equal(t, 16, parts[idx].Start)
+		require.Equal(t, 20, parts[idx].End)
+	})
+
+	t.Run("no known parts", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := SplitUnknown("qwertyuiop", known, opts)
+		require.False(t, ok)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, ok := SplitUnknown(strings.Repeat("Donau", MaxLength/5)+"xxxxx", known, opts)
+		require.False(t, ok)
+	})
+
+	t.Run("ranges are checked once", func(t *testing.T) {
+		t.Parallel()
+
+		// the unknown part must contain both "b", so almost every split is tried
+		half := strings.Repeat("a", MaxLength/2-1) + "b"
+		lookups := 0
+		_, _, ok := SplitUnknown(half+half, func(s string) bool {
+			lookups++
+			return s == "aaaaa"
+		}, opts)
+		require.True(t, ok)
+		require.Less(t, lookups, 100000)
+	})
+}
+
+func Test_MatchCase(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "fahrt", MatchCase("Fahrt", "ahrt"))
+	require.Equal(t, "Donau", MatchCase("donau", "Donu"))
+	require.Equal(t, "", MatchCase("", "Donu"))
+}
@@ -0,0 +1,85 @@
+package langdetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Grams(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{" he", "hel", "ell", "llo", "lo ", " wo", "wor", "orl", "rld", "ld "}, Grams("Hello, world!"))
+	require.Equal(t, []string{" я "}, Grams("Я"))
+	require.Empty(t, Grams("123 ..."))
+}
+
+func Test_Profile_Score(t *testing.T) {
+	t.Parallel()
+
+	en := NewProfile()
+	en.Add("the", "quick", "brown", "fox", "jumps", "over", "lazy", "dog", "hello", "world", "this", "is", "a", "test")
+
+	de := NewProfile()
+	de.Add("der", "schnelle", "braune", "fuchs", "springt", "über", "den", "faulen", "hund", "hallo", "welt", "das", "ist", "ein", "test")
+
+	ru := NewProfile()
+	ru.Add("съешь", "же", "ещё", "этих", "мягких", "французских", "булок", "привет", "мир", "это", "тест")
+
+	tests := []struct {
+		name string
+		text string
+		want *Profile
+	}{
+		{name: "en", text: "Hello, this is the world", want: en},
+		{name: "de", text: "Hallo, das ist der Hund", want: de},
+		{name: "ru", text: "Привет, это мир", want: ru},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			grams := Grams(tt.text)
+
+			var best *Profile
+			var bestScore float64
+			for _, p := range []*Profile{en, de, ru} {
+				if score := p.Score(grams); best == nil || score > bestScore {
+					best, bestScore = p, score
+				}
+			}
+
+			require.Same(t, tt.want, best)
+		})
+	}
+}
+
+func Test_Spans(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "  ", want: []string{}},
+		{name: "single sentence", text: "hello world", want: []string{"hello world"}},
+		{name: "sentences", text: "Hello world. Привет, мир! How are you?", want: []string{"Hello world.", "Привет, мир!", "How are you?"}},
+		{name: "paragraphs", text: "first line\n\n  second line \n", want: []string{"first line", "second line"}},
+		{name: "dot inside a word", text: "version 1.2 is out…", want: []string{"version 1.2 is out…"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := make([]string, 0)
+			for _, s := range Spans(tt.text) {
+				result = append(result, tt.text[s.Start:s.End])
+			}
+
+			require.Equal(t, tt.want, result)
+		})
+	}
+}
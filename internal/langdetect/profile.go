@@ -0,0 +1,82 @@
+package langdetect
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Profile is a character trigram profile of a language built from the words of a dictionary.
+type Profile struct {
+	mu sync.RWMutex
+
+	grams map[string]uint
+	total uint
+}
+
+func NewProfile() *Profile {
+	return &Profile{
+		grams: make(map[string]uint),
+	}
+}
+
+// Add adds trigrams of the words to the profile
+func (p *Profile) Add(words ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, w := range words {
+		for _, g := range Grams(w) {
+			p.grams[g]++
+			p.total++
+		}
+	}
+}
+
+// Len returns the number of distinct trigrams of the profile
+func (p *Profile) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.grams)
+}
+
+// Score returns the mean log-probability of the trigrams in the profile.
+// The higher the score, the more likely the trigrams belong to the profile language.
+func (p *Profile) Score(grams []string) float64 {
+	if len(grams) == 0 {
+		return math.Inf(-1)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// add-one smoothing, so unknown trigrams lower the score instead of zeroing it
+	denominator := float64(p.total + uint(len(p.grams)) + 1)
+
+	var result float64
+	for _, g := range grams {
+		result += math.Log(float64(p.grams[g]+1) / denominator)
+	}
+
+	return result / float64(len(grams))
+}
+
+// Grams splits the text into lowercase words and returns their trigrams.
+// Words are padded with spaces to take the beginning and the end of a word into account.
+func Grams(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	result := make([]string, 0, len(text))
+	for _, w := range words {
+		runes := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result = append(result, string(runes[i:i+3]))
+		}
+	}
+
+	return result
+}
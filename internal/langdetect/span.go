@@ -0,0 +1,66 @@
+package langdetect
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span is a sentence or a paragraph of a text. Offsets are in bytes.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Spans splits the text into sentences. Line breaks always end a sentence.
+func Spans(text string) []Span {
+	result := make([]Span, 0)
+
+	start := 0
+	for i, r := range text {
+		end := -1
+
+		switch r {
+		case '\n':
+			end = i
+		case '.', '!', '?', '…':
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if i+utf8.RuneLen(r) == len(text) || unicode.IsSpace(next) {
+				end = i + utf8.RuneLen(r)
+			}
+		}
+
+		if end < 0 {
+			continue
+		}
+
+		result = appendSpan(result, text, start, end)
+		start = end
+	}
+
+	return appendSpan(result, text, start, len(text))
+}
+
+// appendSpan appends the span with leading and trailing spaces trimmed, empty spans are skipped
+func appendSpan(spans []Span, text string, start int, end int) []Span {
+	for start < end {
+		r, size := utf8.DecodeRuneInString(text[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		start += size
+	}
+
+	for start < end {
+		r, size := utf8.DecodeLastRuneInString(text[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		end -= size
+	}
+
+	if start == end {
+		return spans
+	}
+
+	return append(spans, Span{Start: start, End: end})
+}
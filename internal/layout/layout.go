@@ -0,0 +1,106 @@
+package layout
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnknownLayout = fmt.Errorf("unknown keyboard layout")
+)
+
+// Pair maps keys of one keyboard layout to the same physical keys of another one.
+type Pair struct {
+	from map[rune]rune
+	to   map[rune]rune
+}
+
+// pairs contains supported layout pairs. Both strings of each pair
+// list characters in the same physical key order.
+var pairs = map[string]Pair{
+	"en-ru": newPair(
+		"`qwertyuiop[]asdfghjkl;'zxcvbnm,./"+`~QWERTYUIOP{}ASDFGHJKL:"ZXCVBNM<>?`,
+		"ёйцукенгшщзхъфывапролджэячсмитьбю."+"ЁЙЦУКЕНГШЩЗХЪФЫВАПРОЛДЖЭЯЧСМИТЬБЮ,",
+	),
+}
+
+func newPair(a string, b string) Pair {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) != len(rb) {
+		panic("layout pair must have the same length")
+	}
+
+	result := Pair{
+		from: make(map[rune]rune, len(ra)),
+		to:   make(map[rune]rune, len(rb)),
+	}
+
+	for i := range ra {
+		result.from[ra[i]] = rb[i]
+		result.to[rb[i]] = ra[i]
+	}
+
+	return result
+}
+
+// Get returns a layout pair by its name, e.g. "en-ru".
+func Get(name string) (Pair, error) {
+	p, ok := pairs[name]
+	if !ok {
+		return Pair{}, fmt.Errorf("%w: %q", ErrUnknownLayout, name)
+	}
+
+	return p, nil
+}
+
+// Letters returns the punctuation keys mapped to letters on the other layout of the pair (e.g. ";" => "ж"),
+// such keys are parts of the words typed with a wrong layout.
+func (p Pair) Letters() []rune {
+	var result []rune
+
+	for _, table := range []map[rune]rune{p.from, p.to} {
+		for k, v := range table {
+			if !unicode.IsLetter(k) && unicode.IsLetter(v) {
+				result = append(result, k)
+			}
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// Convert returns the word as if it was typed with the same keys on the other layout of the pair.
+// The direction is detected by the first convertible character.
+// Returns false if the word contains no convertible characters.
+func (p Pair) Convert(word string) (string, bool) {
+	for _, r := range word {
+		if _, ok := p.from[r]; ok {
+			return convert(word, p.from), true
+		}
+
+		if _, ok := p.to[r]; ok {
+			return convert(word, p.to), true
+		}
+	}
+
+	return word, false
+}
+
+func convert(word string, table map[rune]rune) string {
+	var sb strings.Builder
+	sb.Grow(len(word))
+
+	for _, r := range word {
+		if v, ok := table[r]; ok {
+			sb.WriteRune(v)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
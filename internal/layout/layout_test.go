@@ -0,0 +1,64 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Get("xx-yy")
+		require.ErrorIs(t, err, ErrUnknownLayout)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Get("en-ru")
+		require.NoError(t, err)
+	})
+}
+
+func Test_Pair_Letters(t *testing.T) {
+	t.Parallel()
+
+	p, err := Get("en-ru")
+	require.NoError(t, err)
+
+	require.Equal(t, []rune("\"',.:;<>[]`{}~"), p.Letters())
+}
+
+func Test_Pair_Convert(t *testing.T) {
+	t.Parallel()
+
+	p, err := Get("en-ru")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOk bool
+	}{
+		{name: "en to ru", input: "ghbdtn", want: "привет", wantOk: true},
+		{name: "ru to en", input: "руддщ", want: "hello", wantOk: true},
+		{name: "upper case", input: "Ghbdtn", want: "Привет", wantOk: true},
+		{name: "punctuation keys", input: "k.,jdm", want: "любовь", wantOk: true},
+		{name: "not convertible", input: "123", want: "123", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := p.Convert(tt.input)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
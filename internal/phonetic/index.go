@@ -0,0 +1,178 @@
+package phonetic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/agnivade/levenshtein"
+)
+
+const (
+	AlgorithmMetaphone = "metaphone"
+	AlgorithmSoundex   = "soundex"
+)
+
+var (
+	ErrUnknownAlgorithm = fmt.Errorf("unknown phonetic algorithm")
+)
+
+type Match struct {
+	Value string
+	Score float64
+}
+
+// Index maps phonetic keys to dictionary words sounding alike.
+type Index struct {
+	mu sync.RWMutex
+
+	algorithm string
+	encode    func(string) string
+
+	keys   map[string][]string // phonetic key => words
+	counts map[string]uint     // word => weight
+}
+
+func NewIndex(algorithm string) (*Index, error) {
+	encode, err := encoder(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Index{
+		algorithm: algorithm,
+		encode:    encode,
+		keys:      make(map[string][]string),
+		counts:    make(map[string]uint),
+	}, nil
+}
+
+func encoder(algorithm string) (func(string) string, error) {
+	switch algorithm {
+	case AlgorithmMetaphone:
+		return Metaphone, nil
+	case AlgorithmSoundex:
+		return Soundex, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, algorithm)
+	}
+}
+
+func (i *Index) Algorithm() string {
+	return i.algorithm
+}
+
+func (i *Index) AddWeight(weight uint, words ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, w := range words {
+		i.doAdd(w, weight)
+	}
+}
+
+func (i *Index) doAdd(word string, weight uint) {
+	if _, ok := i.counts[word]; ok {
+		i.counts[word] += weight
+		return
+	}
+
+	key := i.encode(word)
+	if key == "" {
+		return
+	}
+
+	i.counts[word] = weight
+	i.keys[key] = append(i.keys[key], word)
+}
+
+// Find returns up to n words with the same phonetic key as the provided word, ordered by score.
+// Scores are comparable to the ones returned by the spellchecker.
+func (i *Index) Find(word string, n int) []Match {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	key := i.encode(word)
+	if key == "" {
+		return nil
+	}
+
+	src := []rune(word)
+	result := make([]Match, 0, len(i.keys[key]))
+
+	for _, candidate := range i.keys[key] {
+		if candidate == word {
+			continue
+		}
+
+		result = append(result, Match{
+			Value: candidate,
+			Score: score(src, []rune(candidate), levenshtein.ComputeDistance(word, candidate), i.counts[candidate]),
+		})
+	}
+
+	sort.Slice(result, func(a, b int) bool { return result[a].Score > result[b].Score })
+
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+
+	return result
+}
+
+// score mirrors the default score function of the spellchecker
+func score(src, candidate []rune, distance int, cnt uint) float64 {
+	mult := math.Log1p(float64(cnt))
+	if len(src) > 0 && len(candidate) > 0 && src[0] == candidate[0] {
+		mult *= 1.5
+		if len(src) > 1 && len(candidate) > 1 && src[1] == candidate[1] {
+			mult *= 1.5
+		}
+	}
+
+	return 1 / (1 + float64(distance*distance)) * mult
+}
+
+type indexData struct {
+	Algorithm string          `json:"algorithm"`
+	Words     map[string]uint `json:"words"`
+}
+
+func (i *Index) MarshalJSON() ([]byte, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return json.Marshal(indexData{
+		Algorithm: i.algorithm,
+		Words:     i.counts,
+	})
+}
+
+func (i *Index) UnmarshalJSON(data []byte) error {
+	var value indexData
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	encode, err := encoder(value.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.algorithm = value.Algorithm
+	i.encode = encode
+	i.keys = make(map[string][]string, len(value.Words))
+	i.counts = make(map[string]uint, len(value.Words))
+
+	for w, cnt := range value.Words {
+		i.doAdd(w, cnt)
+	}
+
+	return nil
+}
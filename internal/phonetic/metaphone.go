@@ -0,0 +1,174 @@
+package phonetic
+
+import (
+	"strings"
+)
+
+// Metaphone returns a phonetic key of the word using the original Metaphone algorithm by Lawrence Philips.
+// Only latin letters are taken into account, an empty string is returned for words without them.
+func Metaphone(word string) string {
+	w := latinUpper(word)
+	if len(w) == 0 {
+		return ""
+	}
+
+	switch {
+	case hasPrefix(w, "AE"), hasPrefix(w, "GN"), hasPrefix(w, "KN"), hasPrefix(w, "PN"), hasPrefix(w, "WR"):
+		w = w[1:]
+	case w[0] == 'X':
+		w[0] = 'S'
+	case hasPrefix(w, "WH"):
+		w = append([]byte{'W'}, w[2:]...)
+	}
+
+	var sb strings.Builder
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+
+		return w[i]
+	}
+
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+
+		// skip duplicate adjacent letters, except C
+		if c != 'C' && i > 0 && at(i-1) == c {
+			continue
+		}
+
+		next := at(i + 1)
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				sb.WriteByte(c)
+			}
+		case 'B':
+			if !(at(i-1) == 'M' && i == len(w)-1) {
+				sb.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case next == 'I' && at(i+2) == 'A':
+				sb.WriteByte('X')
+			case next == 'H':
+				if at(i-1) == 'S' {
+					sb.WriteByte('K')
+				} else {
+					sb.WriteByte('X')
+				}
+				i++
+			case isFrontVowel(next):
+				if at(i-1) != 'S' {
+					sb.WriteByte('S')
+				}
+			default:
+				sb.WriteByte('K')
+			}
+		case 'D':
+			if next == 'G' && isFrontVowel(at(i+2)) {
+				sb.WriteByte('J')
+				i++
+			} else {
+				sb.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case next == 'H' && i+2 < len(w) && !isVowel(at(i+2)):
+				// silent
+			case next == 'N' && (i+2 == len(w) || (at(i+2) == 'E' && at(i+3) == 'D' && i+4 == len(w))):
+				// silent
+			case isFrontVowel(next):
+				sb.WriteByte('J')
+			default:
+				sb.WriteByte('K')
+			}
+		case 'H':
+			prev := at(i - 1)
+			if isVowel(prev) && !isVowel(next) {
+				break
+			}
+			if prev == 'C' || prev == 'S' || prev == 'P' || prev == 'T' || prev == 'G' {
+				break
+			}
+			sb.WriteByte('H')
+		case 'K':
+			if at(i-1) != 'C' {
+				sb.WriteByte('K')
+			}
+		case 'P':
+			if next == 'H' {
+				sb.WriteByte('F')
+			} else {
+				sb.WriteByte('P')
+			}
+		case 'Q':
+			sb.WriteByte('K')
+		case 'S':
+			switch {
+			case next == 'H':
+				sb.WriteByte('X')
+				i++
+			case next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				sb.WriteByte('X')
+			default:
+				sb.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				sb.WriteByte('X')
+			case next == 'H':
+				sb.WriteByte('0')
+				i++
+			case next == 'C' && at(i+2) == 'H':
+				// silent
+			default:
+				sb.WriteByte('T')
+			}
+		case 'V':
+			sb.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(next) {
+				sb.WriteByte(c)
+			}
+		case 'X':
+			sb.WriteString("KS")
+		case 'Z':
+			sb.WriteByte('S')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
+
+func latinUpper(word string) []byte {
+	result := make([]byte, 0, len(word))
+	for _, r := range word {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			result = append(result, byte(r))
+		case r >= 'a' && r <= 'z':
+			result = append(result, byte(r-'a'+'A'))
+		}
+	}
+
+	return result
+}
+
+func hasPrefix(w []byte, prefix string) bool {
+	return len(w) >= len(prefix) && string(w[:len(prefix)]) == prefix
+}
+
+func isVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
+
+func isFrontVowel(c byte) bool {
+	return c == 'E' || c == 'I' || c == 'Y'
+}
@@ -0,0 +1,106 @@
+package phonetic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metaphone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "phonetic", want: "FNTK"},
+		{input: "fonetik", want: "FNTK"},
+		{input: "knight", want: "NT"},
+		{input: "school", want: "SKL"},
+		{input: "thumb", want: "0M"},
+		{input: "xylophone", want: "SLFN"},
+		{input: "nation", want: "NXN"},
+		{input: "привет", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, Metaphone(tt.input))
+		})
+	}
+}
+
+func Test_Soundex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Robert", want: "R163"},
+		{input: "Rupert", want: "R163"},
+		{input: "Ashcraft", want: "A261"},
+		{input: "Tymczak", want: "T522"},
+		{input: "Pfister", want: "P236"},
+		{input: "a", want: "A000"},
+		{input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, Soundex(tt.input))
+		})
+	}
+}
+
+func Test_Index(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewIndex("qwerty")
+		require.ErrorIs(t, err, ErrUnknownAlgorithm)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		t.Parallel()
+
+		idx, err := NewIndex(AlgorithmMetaphone)
+		require.NoError(t, err)
+
+		idx.AddWeight(1, "phonetic", "weapon", "phonetic")
+
+		result := idx.Find("fonetik", 5)
+		require.Len(t, result, 1)
+		require.Equal(t, "phonetic", result[0].Value)
+		require.Greater(t, result[0].Score, 0.0)
+
+		require.Empty(t, idx.Find("phonetic", 5))
+	})
+
+	t.Run("marshal/unmarshal", func(t *testing.T) {
+		t.Parallel()
+
+		idx, err := NewIndex(AlgorithmSoundex)
+		require.NoError(t, err)
+
+		idx.AddWeight(2, "robert")
+
+		data, err := json.Marshal(idx)
+		require.NoError(t, err)
+
+		var idx2 Index
+		require.NoError(t, json.Unmarshal(data, &idx2))
+		require.Equal(t, AlgorithmSoundex, idx2.Algorithm())
+
+		result := idx2.Find("rupert", 5)
+		require.Len(t, result, 1)
+		require.Equal(t, "robert", result[0].Value)
+	})
+}
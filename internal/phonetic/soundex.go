@@ -0,0 +1,40 @@
+package phonetic
+
+var soundexCodes = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', '0', '0', '2', '2', '4', '5',
+	'5', '0', '1', '2', '6', '2', '3', '0', '1', '0', '2', '0', '2',
+}
+
+// Soundex returns an american soundex code of the word.
+// Only latin letters are taken into account, an empty string is returned for words without them.
+func Soundex(word string) string {
+	w := latinUpper(word)
+	if len(w) == 0 {
+		return ""
+	}
+
+	result := []byte{w[0], '0', '0', '0'}
+	last := soundexCodes[w[0]-'A']
+	n := 1
+
+	for _, c := range w[1:] {
+		if n == len(result) {
+			break
+		}
+
+		code := soundexCodes[c-'A']
+
+		switch {
+		case c == 'H' || c == 'W':
+			// does not separate letters with the same code
+		case code == '0':
+			last = code
+		case code != last:
+			result[n] = code
+			n++
+			last = code
+		}
+	}
+
+	return string(result)
+}
@@ -0,0 +1,236 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/logger"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+)
+
+const (
+	statePath      = "/v1/_replication/state"
+	dictionaryPath = "/v1/_replication/dictionaries/"
+)
+
+type replica interface {
+	List() []spellchecker.ListItem
+	ReplaceDictionary(code string, rd io.Reader) error
+	ReplaceMetadata(m spellchecker.Metadata) error
+	Delete(code string, cascade bool) error
+}
+
+// Status describes how far the follower is behind the leader
+type Status struct {
+	Leader      string
+	LastSync    time.Time     // the last time the follower caught up with the leader, zero if never
+	LastAttempt time.Time     // zero if there were no attempts yet
+	Lag         time.Duration // time since the last sync (since the start if there was no sync yet)
+	Pending     int           // dictionaries changed on the leader but not received on the last attempt
+	Error       string        // error of the last attempt
+}
+
+// Follower pulls the state of the leader and applies the changed dictionaries and the aliases to the local registry.
+// Dictionaries missing on the leader are deleted.
+type Follower struct {
+	leader   string
+	registry replica
+	client   *http.Client
+	log      *slog.Logger
+
+	syncMu   sync.Mutex
+	versions map[string]string // versions of the dictionaries received from the leader
+	metadata []byte            // the last metadata applied
+
+	mu      sync.Mutex
+	started time.Time
+	status  Status
+}
+
+func NewFollower(ctx context.Context, leader string, registry replica) (*Follower, error) {
+	u, err := url.Parse(leader)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("leader URL must be absolute: %q", leader)
+	}
+
+	leader = strings.TrimSuffix(leader, "/")
+
+	return &Follower{
+		leader:   leader,
+		registry: registry,
+		client:   &http.Client{},
+		log:      logger.FromContext(ctx),
+		versions: make(map[string]string),
+		started:  time.Now().UTC(),
+		status:   Status{Leader: leader},
+	}, nil
+}
+
+// Leader returns the base URL of the leader
+func (f *Follower) Leader() string {
+	return f.leader
+}
+
+// Run syncs with the leader periodically until the context is done
+func (f *Follower) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.Sync(ctx); err != nil {
+			f.log.Error("replication: sync error", "leader", f.leader, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Follower) Status() Status {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := f.status
+	if result.LastSync.IsZero() {
+		result.Lag = time.Since(f.started)
+	} else {
+		result.Lag = time.Since(result.LastSync)
+	}
+
+	return result
+}
+
+// Sync receives the dictionaries changed since the previous sync and the metadata.
+// The metadata is applied and the removed dictionaries are deleted only if all the changed dictionaries are received,
+// so the aliases never point to dictionaries missing on the follower.
+func (f *Follower) Sync(ctx context.Context) error {
+	f.syncMu.Lock()
+	defer f.syncMu.Unlock()
+
+	start := time.Now().UTC()
+
+	pending, err := f.sync(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.status.LastAttempt = start
+	f.status.Pending = pending
+	f.status.Error = ""
+
+	if err != nil {
+		f.status.Error = err.Error()
+		return err
+	}
+
+	f.status.LastSync = start
+
+	return nil
+}
+
+// sync returns the number of the dictionaries not received
+func (f *Follower) sync(ctx context.Context) (int, error) {
+	var state struct {
+		Dictionaries map[string]string `json:"dictionaries"`
+		Metadata     json.RawMessage   `json:"metadata"`
+	}
+
+	if err := f.get(ctx, statePath, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&state)
+	}); err != nil {
+		return 0, fmt.Errorf("state: %w", err)
+	}
+
+	var errs []error
+
+	pending := 0
+
+	for code, version := range state.Dictionaries {
+		if f.versions[code] == version {
+			continue
+		}
+
+		// the version is taken before the request, so a change made in the meantime is received again on the next sync
+		err := f.get(ctx, dictionaryPath+url.PathEscape(code), func(body io.Reader) error {
+			return f.registry.ReplaceDictionary(code, body)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dictionary %q: %w", code, err))
+			pending++
+
+			continue
+		}
+
+		f.versions[code] = version
+		f.log.Info("replication: dictionary received", "dictionary", code)
+	}
+
+	if len(errs) > 0 {
+		return pending, errors.Join(errs...)
+	}
+
+	if !bytes.Equal(f.metadata, state.Metadata) {
+		var metadata spellchecker.Metadata
+		if err := json.Unmarshal(state.Metadata, &metadata); err != nil {
+			return 0, fmt.Errorf("metadata: %w", err)
+		}
+
+		if err := f.registry.ReplaceMetadata(metadata); err != nil {
+			return 0, fmt.Errorf("metadata: %w", err)
+		}
+
+		f.metadata = state.Metadata
+	}
+
+	for _, item := range f.registry.List() {
+		if _, ok := state.Dictionaries[item.Code]; ok {
+			continue
+		}
+
+		if err := f.registry.Delete(item.Code, true); err != nil && !errors.Is(err, spellchecker.ErrNotFound) {
+			return 0, fmt.Errorf("dictionary %q delete: %w", item.Code, err)
+		}
+
+		delete(f.versions, item.Code)
+		f.log.Info("replication: dictionary deleted", "dictionary", item.Code)
+	}
+
+	return 0, nil
+}
+
+func (f *Follower) get(ctx context.Context, path string, read func(body io.Reader) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.leader+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return read(resp.Body)
+}
@@ -0,0 +1,168 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLeader serves the replication endpoints of the registry
+func newTestLeader(t *testing.T, registry *spellchecker.Registry) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+statePath, func(w http.ResponseWriter, r *http.Request) {
+		state, err := registry.ReplicationState()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(state)
+	})
+
+	mux.HandleFunc("GET "+dictionaryPath+"{code}", func(w http.ResponseWriter, r *http.Request) {
+		err := registry.WriteDictionary(r.PathValue("code"), w)
+		if errors.Is(err, spellchecker.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestRegistry(t *testing.T) *spellchecker.Registry {
+	result, err := spellchecker.NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	return result
+}
+
+func Test_Follower_Sync(t *testing.T) {
+	t.Parallel()
+
+	leader := newTestRegistry(t)
+	server := newTestLeader(t, leader)
+
+	registry := newTestRegistry(t)
+	_, err := registry.Add("local", spellchecker.Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	f, err := NewFollower(context.Background(), server.URL+"/", registry)
+	require.NoError(t, err)
+
+	_, err = leader.Add("code", spellchecker.Options{Alphabet: "abc"})
+	require.NoError(t, err)
+	require.NoError(t, leader.SetAlias("alias", "code"))
+
+	item, err := leader.GetItem("code")
+	require.NoError(t, err)
+	item.AddWeight(1, "abc")
+
+	require.NoError(t, f.Sync(context.Background()))
+
+	// the dictionaries missing on the leader are deleted
+	require.Len(t, registry.List(), 1)
+
+	item, err = registry.GetItem("alias")
+	require.NoError(t, err)
+	require.True(t, item.Spellchecker.IsCorrect("abc"))
+
+	status := f.Status()
+	require.Equal(t, server.URL, status.Leader)
+	require.False(t, status.LastSync.IsZero())
+	require.Empty(t, status.Error)
+
+	// only the changed dictionaries are received again
+	version := f.versions["code"]
+
+	require.NoError(t, f.Sync(context.Background()))
+	require.Equal(t, version, f.versions["code"])
+
+	item, err = leader.GetItem("code")
+	require.NoError(t, err)
+	item.AddWeight(1, "cab")
+
+	require.NoError(t, f.Sync(context.Background()))
+	require.NotEqual(t, version, f.versions["code"])
+
+	item, err = registry.GetItem("code")
+	require.NoError(t, err)
+	require.True(t, item.Spellchecker.IsCorrect("cab"))
+
+	require.NoError(t, leader.Delete("code", true))
+	require.NoError(t, f.Sync(context.Background()))
+	require.Empty(t, registry.List())
+
+	_, err = registry.GetCodeByAlias("alias")
+	require.ErrorIs(t, err, spellchecker.ErrAliasNotFound)
+}
+
+func Test_Follower_Sync_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == statePath {
+			_, _ = w.Write([]byte(`{"dictionaries":{"code":"1"},"metadata":{"aliases":{"alias":"code"}}}`))
+			return
+		}
+
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	registry := newTestRegistry(t)
+
+	f, err := NewFollower(context.Background(), server.URL, registry)
+	require.NoError(t, err)
+
+	err = f.Sync(context.Background())
+	require.Error(t, err)
+
+	status := f.Status()
+	require.True(t, status.LastSync.IsZero())
+	require.False(t, status.LastAttempt.IsZero())
+	require.Equal(t, 1, status.Pending)
+	require.True(t, strings.Contains(status.Error, "boom"))
+	require.Positive(t, status.Lag)
+
+	// the metadata is not applied until all the dictionaries are received
+	_, err = registry.GetCodeByAlias("alias")
+	require.ErrorIs(t, err, spellchecker.ErrAliasNotFound)
+}
+
+func Test_NewFollower(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		leader  string
+		wantErr bool
+	}{
+		{leader: "http://leader:8011"},
+		{leader: "https://leader"},
+		{leader: "leader:8011", wantErr: true},
+		{leader: "/v1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.leader, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewFollower(context.Background(), tt.leader, newTestRegistry(t))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -22,6 +22,8 @@ func aliasDelete(registry aliasDeleter) usecase.Interactor {
 		err := registry.DeleteAlias(input.Alias)
 		if errors.Is(spellchecker.ErrAliasNotFound, err) {
 			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrHasAliases) {
+			return status.Wrap(err, status.FailedPrecondition)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
@@ -30,8 +32,8 @@ func aliasDelete(registry aliasDeleter) usecase.Interactor {
 	})
 
 	u.SetTitle("Delete alias from a dictionary")
-	u.SetDescription("Removes an alias from a dictionary.")
-	u.SetExpectedErrors(status.Internal, status.NotFound)
+	u.SetDescription("Removes an alias from a dictionary. An alias used by other aliases can not be removed.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.FailedPrecondition)
 
 	return u
 }
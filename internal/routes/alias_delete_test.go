@@ -42,6 +42,13 @@ func Test_AliasDelete(t *testing.T) {
 			wantErr:  true,
 			wantCode: status.NotFound,
 		},
+		{
+			name:     "used by other aliases",
+			deleter:  &testAliasDeleter{err: spellchecker.ErrHasAliases},
+			input:    AliasDeleteRequest{Alias: "eng"},
+			wantErr:  true,
+			wantCode: status.FailedPrecondition,
+		},
 		{
 			name:     "internal error",
 			deleter:  &testAliasDeleter{err: errors.New("boom")},
@@ -10,7 +10,7 @@ import (
 )
 
 type aliasGetter interface {
-	GetCodeByAlias(alias string) (string, error)
+	GetAlias(alias string) (spellchecker.Alias, error)
 }
 
 type AliasGetRequest struct {
@@ -18,19 +18,21 @@ type AliasGetRequest struct {
 }
 
 type AliasGetResponse struct {
-	Dictionary string `json:"dictionary"`
+	Dictionary string        `json:"dictionary" description:"Dictionary (or alias) the alias points to. The first target for an alias with several targets."`
+	Targets    []AliasTarget `json:"targets,omitempty" description:"Weighted targets of the alias, if there are several of them."`
 }
 
 func aliasGet(registry aliasGetter) usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input AliasGetRequest, output *AliasGetResponse) error {
-		code, err := registry.GetCodeByAlias(input.Alias)
-		if errors.Is(spellchecker.ErrNotFound, err) {
+		alias, err := registry.GetAlias(input.Alias)
+		if errors.Is(err, spellchecker.ErrNotFound) || errors.Is(err, spellchecker.ErrAliasNotFound) {
 			return status.Wrap(err, status.NotFound)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
 
-		output.Dictionary = code
+		output.Dictionary = alias.Dictionary
+		output.Targets = aliasTargets(alias.Targets)
 
 		return nil
 	})
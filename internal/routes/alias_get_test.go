@@ -11,24 +11,26 @@ import (
 )
 
 type testAliasGetter struct {
-	code string
-	err  error
+	code    string
+	targets []spellchecker.AliasTarget
+	err     error
 }
 
-func (f *testAliasGetter) GetCodeByAlias(alias string) (string, error) {
-	return f.code, f.err
+func (f *testAliasGetter) GetAlias(alias string) (spellchecker.Alias, error) {
+	return spellchecker.Alias{Dictionary: f.code, Targets: f.targets}, f.err
 }
 
 func Test_AliasGet(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name     string
-		getter   *testAliasGetter
-		input    AliasGetRequest
-		wantErr  bool
-		wantCode status.Code
-		wantDict string
+		name        string
+		getter      *testAliasGetter
+		input       AliasGetRequest
+		wantErr     bool
+		wantCode    status.Code
+		wantDict    string
+		wantTargets []AliasTarget
 	}{
 		{
 			name:     "success",
@@ -38,6 +40,27 @@ func Test_AliasGet(t *testing.T) {
 			wantCode: status.OK,
 			wantDict: "en",
 		},
+		{
+			name: "several targets",
+			getter: &testAliasGetter{code: "en-v1", targets: []spellchecker.AliasTarget{
+				{Dictionary: "en-v1", Weight: 90},
+				{Dictionary: "en-v2", Weight: 10},
+			}},
+			input:    AliasGetRequest{Alias: "eng"},
+			wantCode: status.OK,
+			wantDict: "en-v1",
+			wantTargets: []AliasTarget{
+				{Dictionary: "en-v1", Weight: 90},
+				{Dictionary: "en-v2", Weight: 10},
+			},
+		},
+		{
+			name:     "alias not found",
+			getter:   &testAliasGetter{code: "", err: spellchecker.ErrAliasNotFound},
+			input:    AliasGetRequest{Alias: "xx"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
 		{
 			name:     "not found",
 			getter:   &testAliasGetter{code: "", err: spellchecker.ErrNotFound},
@@ -69,6 +92,7 @@ func Test_AliasGet(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, tt.wantDict, out.Dictionary)
+				require.Equal(t, tt.wantTargets, out.Targets)
 			}
 		})
 	}
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type aliasHistorian interface {
+	AliasHistory(alias string) []spellchecker.AliasChange
+}
+
+type AliasHistoryRequest struct {
+	Alias string `path:"alias" minLength:"1"`
+}
+
+type AliasHistoryResponse struct {
+	Items []AliasChange `json:"items"`
+}
+
+type AliasChange struct {
+	From string    `json:"from,omitempty" description:"Previous dictionary. Empty if the alias was created."`
+	To   string    `json:"to,omitempty" description:"New dictionary. Empty if the alias was deleted."`
+	Time time.Time `json:"time"`
+}
+
+func aliasHistory(registry aliasHistorian) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input AliasHistoryRequest, output *AliasHistoryResponse) error {
+		changes := registry.AliasHistory(input.Alias)
+
+		output.Items = make([]AliasChange, 0, len(changes))
+		for _, c := range changes {
+			output.Items = append(output.Items, AliasChange{From: c.From, To: c.To, Time: c.Time})
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Get dictionary alias history")
+	u.SetDescription("Returns changes of the alias, the newest first")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
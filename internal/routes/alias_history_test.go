@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+)
+
+type testAliasHistorian struct {
+	changes []spellchecker.AliasChange
+}
+
+func (f *testAliasHistorian) AliasHistory(alias string) []spellchecker.AliasChange {
+	return f.changes
+}
+
+func Test_AliasHistory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	historian := &testAliasHistorian{changes: []spellchecker.AliasChange{
+		{Alias: "prod-en", From: "en-v1", To: "en-v2", Time: now},
+		{Alias: "prod-en", To: "en-v1", Time: now},
+	}}
+
+	var out AliasHistoryResponse
+	err := aliasHistory(historian).Interact(context.Background(), AliasHistoryRequest{Alias: "prod-en"}, &out)
+
+	require.NoError(t, err)
+	require.Equal(t, []AliasChange{
+		{From: "en-v1", To: "en-v2", Time: now},
+		{To: "en-v1", Time: now},
+	}, out.Items)
+}
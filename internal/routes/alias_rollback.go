@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type aliasRollbacker interface {
+	RollbackAlias(alias string) (string, error)
+}
+
+type AliasRollbackRequest struct {
+	Alias string `path:"alias" minLength:"1"`
+}
+
+type AliasRollbackResponse struct {
+	Dictionary string `json:"dictionary" description:"Dictionary the alias points to after the rollback."`
+}
+
+func aliasRollback(registry aliasRollbacker) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input AliasRollbackRequest, output *AliasRollbackResponse) error {
+		code, err := registry.RollbackAlias(input.Alias)
+		if errors.Is(err, spellchecker.ErrAliasNotFound) || errors.Is(err, spellchecker.ErrNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrNoPreviousTarget) {
+			return status.Wrap(err, status.FailedPrecondition)
+		} else if errors.Is(err, spellchecker.ErrAlreadyExists) {
+			return status.Wrap(err, status.AlreadyExists)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Dictionary = code
+
+		return nil
+	})
+
+	u.SetTitle("Roll back dictionary alias")
+	u.SetDescription("Points the alias to the dictionary it pointed to before the last change. A deleted alias is restored.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.FailedPrecondition, status.AlreadyExists)
+
+	return u
+}
@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testAliasRollbacker struct {
+	code string
+	err  error
+}
+
+func (f *testAliasRollbacker) RollbackAlias(alias string) (string, error) {
+	return f.code, f.err
+}
+
+func Test_AliasRollback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		rollbacker *testAliasRollbacker
+		wantErr    bool
+		wantCode   status.Code
+	}{
+		{
+			name:       "success",
+			rollbacker: &testAliasRollbacker{code: "en-v1"},
+		},
+		{
+			name:       "alias not found",
+			rollbacker: &testAliasRollbacker{err: spellchecker.ErrAliasNotFound},
+			wantErr:    true,
+			wantCode:   status.NotFound,
+		},
+		{
+			name:       "dictionary not found",
+			rollbacker: &testAliasRollbacker{err: spellchecker.ErrNotFound},
+			wantErr:    true,
+			wantCode:   status.NotFound,
+		},
+		{
+			name:       "no previous target",
+			rollbacker: &testAliasRollbacker{err: spellchecker.ErrNoPreviousTarget},
+			wantErr:    true,
+			wantCode:   status.FailedPrecondition,
+		},
+		{
+			name:       "dictionary name",
+			rollbacker: &testAliasRollbacker{err: spellchecker.ErrAlreadyExists},
+			wantErr:    true,
+			wantCode:   status.AlreadyExists,
+		},
+		{
+			name:       "internal error",
+			rollbacker: &testAliasRollbacker{err: errors.New("boom")},
+			wantErr:    true,
+			wantCode:   status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out AliasRollbackResponse
+			err := aliasRollback(tt.rollbacker).Interact(context.Background(), AliasRollbackRequest{Alias: "prod-en"}, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "en-v1", out.Dictionary)
+			}
+		})
+	}
+}
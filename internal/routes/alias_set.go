@@ -11,18 +11,40 @@ import (
 
 type aliasSetter interface {
 	SetAlias(alias string, code string) error
+	SetAliasTargets(alias string, targets []spellchecker.AliasTarget) error
 }
 
 type AliasSetRequest struct {
-	Alias      string `path:"alias" minLength:"1" description:"Alias to set to the dictionary"`
-	Dictionary string `json:"dictionary"`
+	Alias      string        `path:"alias" minLength:"1" description:"Alias to set to the dictionary"`
+	Dictionary string        `json:"dictionary,omitempty" description:"Dictionary or another alias to point to."`
+	Targets    []AliasTarget `json:"targets,omitempty" description:"Several dictionaries (or aliases) to split requests between. Used instead of dictionary."`
+}
+
+type AliasTarget struct {
+	Dictionary string `json:"dictionary" minLength:"1"`
+	Weight     uint   `json:"weight" minimum:"1" description:"Share of requests routed to the dictionary, relative to the weights of the other targets."`
 }
 
 func aliasSet(registry aliasSetter) usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input AliasSetRequest, output *Empty) error {
-		err := registry.SetAlias(input.Alias, input.Dictionary)
+		var err error
+		if len(input.Targets) > 0 {
+			targets := make([]spellchecker.AliasTarget, 0, len(input.Targets))
+			for _, t := range input.Targets {
+				targets = append(targets, spellchecker.AliasTarget{Dictionary: t.Dictionary, Weight: t.Weight})
+			}
+
+			err = registry.SetAliasTargets(input.Alias, targets)
+		} else {
+			err = registry.SetAlias(input.Alias, input.Dictionary)
+		}
+
 		if errors.Is(spellchecker.ErrAliasNotFound, err) {
 			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrAlreadyExists) {
+			return status.Wrap(err, status.AlreadyExists)
+		} else if errors.Is(err, spellchecker.ErrAliasCycle) || errors.Is(err, spellchecker.ErrInvalidAliasTargets) {
+			return status.Wrap(err, status.InvalidArgument)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
@@ -31,8 +53,21 @@ func aliasSet(registry aliasSetter) usecase.Interactor {
 	})
 
 	u.SetTitle("Set dictionary alias")
-	u.SetDescription("Assigns an alias to a dictionary. If the alias is already used by another dictionary, it will be reassigned to the current one. This route can be used, for example, to manage dictionary versioning.")
-	u.SetExpectedErrors(status.Internal, status.NotFound)
+	u.SetDescription("Assigns an alias to a dictionary. If the alias is already used by another dictionary, it will be reassigned to the current one. This route can be used, for example, to manage dictionary versioning. An alias can point to another alias or split requests between several dictionaries by weights (e.g. for A/B testing).")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument, status.AlreadyExists)
 
 	return u
 }
+
+func aliasTargets(targets []spellchecker.AliasTarget) []AliasTarget {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	result := make([]AliasTarget, 0, len(targets))
+	for _, t := range targets {
+		result = append(result, AliasTarget{Dictionary: t.Dictionary, Weight: t.Weight})
+	}
+
+	return result
+}
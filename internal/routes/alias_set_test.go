@@ -11,10 +11,17 @@ import (
 )
 
 type testAliasSetter struct {
-	err error
+	targets []spellchecker.AliasTarget
+	err     error
 }
 
 func (f *testAliasSetter) SetAlias(alias string, code string) error {
+	f.targets = []spellchecker.AliasTarget{{Dictionary: code}}
+	return f.err
+}
+
+func (f *testAliasSetter) SetAliasTargets(alias string, targets []spellchecker.AliasTarget) error {
+	f.targets = targets
 	return f.err
 }
 
@@ -35,6 +42,37 @@ func Test_AliasSet(t *testing.T) {
 			wantErr:  false,
 			wantCode: status.OK,
 		},
+		{
+			name:   "several targets",
+			setter: &testAliasSetter{err: nil},
+			input: AliasSetRequest{Alias: "eng", Targets: []AliasTarget{
+				{Dictionary: "en-v1", Weight: 90},
+				{Dictionary: "en-v2", Weight: 10},
+			}},
+			wantErr:  false,
+			wantCode: status.OK,
+		},
+		{
+			name:     "cycle",
+			setter:   &testAliasSetter{err: spellchecker.ErrAliasCycle},
+			input:    AliasSetRequest{Alias: "eng", Dictionary: "eng"},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "invalid targets",
+			setter:   &testAliasSetter{err: spellchecker.ErrInvalidAliasTargets},
+			input:    AliasSetRequest{Alias: "eng", Targets: []AliasTarget{{Dictionary: "en"}, {Dictionary: "en"}}},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "dictionary name",
+			setter:   &testAliasSetter{err: spellchecker.ErrAlreadyExists},
+			input:    AliasSetRequest{Alias: "en", Dictionary: "en-v2"},
+			wantErr:  true,
+			wantCode: status.AlreadyExists,
+		},
 		{
 			name:     "alias not found",
 			setter:   &testAliasSetter{err: spellchecker.ErrAliasNotFound},
@@ -60,6 +98,13 @@ func Test_AliasSet(t *testing.T) {
 			var out Empty
 			err := interactor.Interact(context.Background(), tt.input, &out)
 
+			if len(tt.input.Targets) > 0 {
+				require.Len(t, tt.setter.targets, len(tt.input.Targets))
+				require.Equal(t, tt.input.Targets[0].Weight, tt.setter.targets[0].Weight)
+			} else {
+				require.Equal(t, []spellchecker.AliasTarget{{Dictionary: tt.input.Dictionary}}, tt.setter.targets)
+			}
+
 			if tt.wantErr {
 				require.Error(t, err)
 				require.True(t, err.(isErr).Is(tt.wantCode))
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type aliasSwapper interface {
+	SwapAliases(aliases map[string]string) error
+}
+
+type AliasSwapRequest struct {
+	Aliases map[string]string `json:"aliases" minProperties:"1" description:"Aliases to set: alias => dictionary."`
+}
+
+func aliasSwap(registry aliasSwapper) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input AliasSwapRequest, output *Empty) error {
+		err := registry.SwapAliases(input.Aliases)
+		if errors.Is(err, spellchecker.ErrNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrAlreadyExists) {
+			return status.Wrap(err, status.AlreadyExists)
+		} else if errors.Is(err, spellchecker.ErrAliasCycle) {
+			return status.Wrap(err, status.InvalidArgument)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Swap dictionary aliases")
+	u.SetDescription("Points several aliases to their dictionaries at once. Either all the aliases are changed or none of them (e.g. if one of the dictionaries does not exist).")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument, status.AlreadyExists)
+
+	return u
+}
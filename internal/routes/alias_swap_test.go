@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testAliasSwapper struct {
+	aliases map[string]string
+	err     error
+}
+
+func (f *testAliasSwapper) SwapAliases(aliases map[string]string) error {
+	f.aliases = aliases
+	return f.err
+}
+
+func Test_AliasSwap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		swapper  *testAliasSwapper
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:    "success",
+			swapper: &testAliasSwapper{},
+		},
+		{
+			name:     "dictionary not found",
+			swapper:  &testAliasSwapper{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "dictionary name",
+			swapper:  &testAliasSwapper{err: spellchecker.ErrAlreadyExists},
+			wantErr:  true,
+			wantCode: status.AlreadyExists,
+		},
+		{
+			name:     "cycle",
+			swapper:  &testAliasSwapper{err: spellchecker.ErrAliasCycle},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "internal error",
+			swapper:  &testAliasSwapper{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := AliasSwapRequest{Aliases: map[string]string{"prod-en": "en-v2", "prod-en-medical": "medical-v2"}}
+
+			var out Empty
+			err := aliasSwap(tt.swapper).Interact(context.Background(), input, &out)
+
+			require.Equal(t, input.Aliases, tt.swapper.aliases)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
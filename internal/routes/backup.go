@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type backuper interface {
+	Backup(w io.Writer, compress bool) error
+}
+
+type BackupRequest struct {
+	Gzip bool `query:"gzip" default:"true" description:"Compress the archive with gzip."`
+}
+
+type BackupResponse struct {
+	usecase.OutputWithEmbeddedWriter
+
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+}
+
+func backup(registry backuper) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input BackupRequest, output *BackupResponse) error {
+		name := "spellchecker-" + time.Now().UTC().Format("20060102T150405Z") + ".tar"
+
+		output.ContentType = "application/x-tar"
+		if input.Gzip {
+			name += ".gz"
+			output.ContentType = "application/gzip"
+		}
+
+		output.ContentDisposition = fmt.Sprintf("attachment; filename=%q", name)
+
+		if err := registry.Backup(output, input.Gzip); err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Backup the registry")
+	u.SetDescription("Streams a tar archive with all the dictionaries and the metadata (aliases). The files are taken at the same moment, so the archive is consistent. Use POST /v1/_restore to restore it.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
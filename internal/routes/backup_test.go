@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testBackuper struct {
+	compress bool
+	err      error
+}
+
+func (f *testBackuper) Backup(w io.Writer, compress bool) error {
+	f.compress = compress
+
+	if f.err != nil {
+		return f.err
+	}
+
+	_, err := w.Write([]byte("archive"))
+
+	return err
+}
+
+func Test_Backup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		registry        *testBackuper
+		input           BackupRequest
+		wantContentType string
+		wantCode        status.Code
+	}{
+		{
+			name:            "gzip",
+			registry:        &testBackuper{},
+			input:           BackupRequest{Gzip: true},
+			wantContentType: "application/gzip",
+		},
+		{
+			name:            "tar",
+			registry:        &testBackuper{},
+			input:           BackupRequest{},
+			wantContentType: "application/x-tar",
+		},
+		{
+			name:     "internal error",
+			registry: &testBackuper{err: errors.New("boom")},
+			input:    BackupRequest{Gzip: true},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			out := BackupResponse{}
+			out.SetWriter(&buf)
+
+			err := backup(tt.registry).Interact(context.Background(), tt.input, &out)
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.input.Gzip, tt.registry.compress)
+			require.Equal(t, tt.wantContentType, out.ContentType)
+			require.Contains(t, out.ContentDisposition, "attachment")
+			require.Equal(t, "archive", buf.String())
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/f1monkey/spellchecker-web/internal/cluster"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type rebalancer interface {
+	Rebalance(ctx context.Context) (cluster.RebalanceResult, error)
+}
+
+type ClusterRebalanceResponse struct {
+	Dictionaries []string `json:"dictionaries" description:"Dictionaries moved to the peers owning them."`
+	Aliases      []string `json:"aliases" description:"Aliases moved to the peers owning them."`
+	Errors       []string `json:"errors,omitempty" description:"Dictionaries and aliases failed to move, they are kept locally."`
+}
+
+func clusterRebalance(cluster rebalancer) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *ClusterRebalanceResponse) error {
+		result, err := cluster.Rebalance(ctx)
+		if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Dictionaries = result.Dictionaries
+		output.Aliases = result.Aliases
+		output.Errors = result.Errors
+
+		if output.Dictionaries == nil {
+			output.Dictionaries = []string{}
+		}
+
+		if output.Aliases == nil {
+			output.Aliases = []string{}
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Rebalance the cluster")
+	u.SetDescription("Moves the local dictionaries and aliases owned by other peers to their owners. It is done on startup automatically, call it after a failed attempt.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
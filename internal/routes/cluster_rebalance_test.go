@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/cluster"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testRebalancer struct {
+	result cluster.RebalanceResult
+	err    error
+}
+
+func (f *testRebalancer) Rebalance(ctx context.Context) (cluster.RebalanceResult, error) {
+	return f.result, f.err
+}
+
+func Test_ClusterRebalance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cluster  *testRebalancer
+		want     ClusterRebalanceResponse
+		wantCode status.Code
+	}{
+		{
+			name:    "nothing to move",
+			cluster: &testRebalancer{},
+			want:    ClusterRebalanceResponse{Dictionaries: []string{}, Aliases: []string{}},
+		},
+		{
+			name: "moved",
+			cluster: &testRebalancer{result: cluster.RebalanceResult{
+				Dictionaries: []string{"shop:en"},
+				Aliases:      []string{"shop:main"},
+				Errors:       []string{`dictionary "de": unexpected status 502`},
+			}},
+			want: ClusterRebalanceResponse{
+				Dictionaries: []string{"shop:en"},
+				Aliases:      []string{"shop:main"},
+				Errors:       []string{`dictionary "de": unexpected status 502`},
+			},
+		},
+		{
+			name:     "internal error",
+			cluster:  &testRebalancer{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out ClusterRebalanceResponse
+			err := clusterRebalance(tt.cluster).Interact(context.Background(), Empty{}, &out)
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out)
+		})
+	}
+}
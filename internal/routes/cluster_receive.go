@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/rest/request"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type dictionaryReplacer interface {
+	ReplaceDictionary(code string, rd io.Reader) error
+}
+
+type ClusterReceiveRequest struct {
+	request.EmbeddedSetter
+
+	Code string `path:"code" minLength:"1"`
+}
+
+func clusterReceive(registry dictionaryReplacer) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input ClusterReceiveRequest, output *Empty) error {
+		err := registry.ReplaceDictionary(input.Code, input.Request().Body)
+		if errors.Is(err, spellchecker.ErrCorrupted) || errors.Is(err, spellchecker.ErrUnsupportedFormat) || errors.Is(err, spellchecker.ErrInvalidOptions) {
+			return status.Wrap(err, status.InvalidArgument)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Receive a dictionary from a peer")
+	u.SetDescription("Adds (or replaces) the dictionary from the file passed as the request body. Used by the peers to move the dictionaries on rebalance.")
+	u.SetExpectedErrors(status.InvalidArgument, status.Internal)
+
+	return u
+}
@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testDictionaryReplacer struct {
+	code string
+	body string
+	err  error
+}
+
+func (f *testDictionaryReplacer) ReplaceDictionary(code string, rd io.Reader) error {
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+
+	f.code = code
+	f.body = string(data)
+
+	return f.err
+}
+
+func Test_ClusterReceive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		registry *testDictionaryReplacer
+		wantCode status.Code
+	}{
+		{
+			name:     "success",
+			registry: &testDictionaryReplacer{},
+		},
+		{
+			name:     "corrupted file",
+			registry: &testDictionaryReplacer{err: fmt.Errorf("%w: unexpected EOF", spellchecker.ErrCorrupted)},
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "invalid code",
+			registry: &testDictionaryReplacer{err: fmt.Errorf("%w: invalid code", spellchecker.ErrInvalidOptions)},
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "internal error",
+			registry: &testDictionaryReplacer{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := ClusterReceiveRequest{Code: "en"}
+			input.SetRequest(httptest.NewRequest("PUT", "/v1/_cluster/dictionaries/en", strings.NewReader("file")))
+
+			err := clusterReceive(tt.registry).Interact(context.Background(), input, &Empty{})
+
+			require.Equal(t, "en", tt.registry.code)
+			require.Equal(t, "file", tt.registry.body)
+
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type clusterInfo interface {
+	Self() string
+	Peers() []string
+	Misplaced() ([]string, error)
+}
+
+type ClusterStatusResponse struct {
+	Self      string   `json:"self" description:"URL of this instance."`
+	Peers     []string `json:"peers" description:"URLs of all the instances of the cluster."`
+	Misplaced []string `json:"misplaced" description:"Local dictionaries and aliases owned by other peers, they are moved by POST /v1/_cluster/rebalance."`
+}
+
+func clusterStatus(cluster clusterInfo) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *ClusterStatusResponse) error {
+		misplaced, err := cluster.Misplaced()
+		if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Self = cluster.Self()
+		output.Peers = cluster.Peers()
+		output.Misplaced = misplaced
+
+		if output.Misplaced == nil {
+			output.Misplaced = []string{}
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Get the cluster status")
+	u.SetDescription("Returns the peers of the cluster and the local dictionaries and aliases which should be moved to other peers.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testClusterInfo struct {
+	misplaced []string
+	err       error
+}
+
+func (f *testClusterInfo) Self() string {
+	return "http://a:8011"
+}
+
+func (f *testClusterInfo) Peers() []string {
+	return []string{"http://a:8011", "http://b:8011"}
+}
+
+func (f *testClusterInfo) Misplaced() ([]string, error) {
+	return f.misplaced, f.err
+}
+
+func Test_ClusterStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cluster  *testClusterInfo
+		want     ClusterStatusResponse
+		wantCode status.Code
+	}{
+		{
+			name:    "balanced",
+			cluster: &testClusterInfo{},
+			want:    ClusterStatusResponse{Self: "http://a:8011", Peers: []string{"http://a:8011", "http://b:8011"}, Misplaced: []string{}},
+		},
+		{
+			name:    "misplaced",
+			cluster: &testClusterInfo{misplaced: []string{"en"}},
+			want:    ClusterStatusResponse{Self: "http://a:8011", Peers: []string{"http://a:8011", "http://b:8011"}, Misplaced: []string{"en"}},
+		},
+		{
+			name:     "internal error",
+			cluster:  &testClusterInfo{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out ClusterStatusResponse
+			err := clusterStatus(tt.cluster).Interact(context.Background(), Empty{}, &out)
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryCandidateApproveRequest struct {
+	Code string `path:"code" minLength:"1"`
+	Word string `path:"word" minLength:"1"`
+
+	Weight uint `json:"weight" min:"1" description:"Weight of the word added to the dictionary."`
+}
+
+func dictionaryCandidateApprove(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryCandidateApproveRequest, output *Empty) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		weight := input.Weight
+		if weight == 0 {
+			weight = 1
+		}
+
+		err = item.ApproveCandidate(input.Word, weight)
+		if errors.Is(err, spellchecker.ErrCandidatesDisabled) {
+			return status.Wrap(err, status.FailedPrecondition)
+		} else if errors.Is(err, spellchecker.ErrCandidateNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Approve a candidate word")
+	u.SetDescription("Adds the candidate word to the dictionary and removes it from the candidates list.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.FailedPrecondition)
+
+	return u
+}
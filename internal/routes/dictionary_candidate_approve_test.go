@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryCandidateApprove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		getter := newTestCandidatesGetter(t, 0, "foo bar")
+
+		var out Empty
+		err := dictionaryCandidateApprove(getter).Interact(context.Background(), DictionaryCandidateApproveRequest{Code: "en", Word: "foo"}, &out)
+		require.NoError(t, err)
+		require.True(t, getter.sc.IsCorrect("foo"))
+
+		var list DictionaryCandidateListResponse
+		err = dictionaryCandidateList(getter).Interact(context.Background(), DictionaryCandidateListRequest{Code: "en"}, &list)
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		require.Equal(t, "bar", list.Items[0].Word)
+	})
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		wantCode status.Code
+	}{
+		{
+			name:     "candidate not found",
+			getter:   newTestCandidatesGetter(t, 0, "bar"),
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "disabled",
+			getter:   &testDictionaryGetter{},
+			wantCode: status.FailedPrecondition,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryCandidateApprove(tt.getter).Interact(context.Background(), DictionaryCandidateApproveRequest{Code: "en", Word: "foo"}, &out)
+			require.Error(t, err)
+			require.True(t, err.(isErr).Is(tt.wantCode))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryCandidateListRequest struct {
+	Code string `path:"code" minLength:"1"`
+}
+
+type DictionaryCandidateListResponse struct {
+	Items []CandidateItem `json:"items"`
+}
+
+type CandidateItem struct {
+	Word      string    `json:"word"`
+	Count     uint      `json:"count" description:"Number of times the word was found by fix requests."`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Contexts  []string  `json:"contexts" description:"Sample text fragments containing the word."`
+}
+
+func dictionaryCandidateList(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryCandidateListRequest, output *DictionaryCandidateListResponse) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		candidates, err := item.ListCandidates()
+		if errors.Is(err, spellchecker.ErrCandidatesDisabled) {
+			return status.Wrap(err, status.FailedPrecondition)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		result := make([]CandidateItem, 0, len(candidates))
+
+		for _, c := range candidates {
+			result = append(result, CandidateItem{
+				Word:      c.Word,
+				Count:     c.Count,
+				FirstSeen: c.FirstSeen,
+				LastSeen:  c.LastSeen,
+				Contexts:  c.Contexts,
+			})
+		}
+
+		output.Items = result
+
+		return nil
+	})
+
+	u.SetTitle("List candidate words")
+	u.SetDescription("Returns unknown words collected by fix requests, the most frequent first. Candidates collection must be enabled for the dictionary.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.FailedPrecondition)
+
+	return u
+}
@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+var testSplitter = regexp.MustCompile(`[a-z]+`)
+
+// newTestCandidatesGetter returns a getter for a dictionary with the candidates collected from the text
+func newTestCandidatesGetter(t *testing.T, autoApprove uint, text string) *testDictionaryGetter {
+	t.Helper()
+
+	sc, err := f1mspellchecker.New(f1mspellchecker.DefaultAlphabet)
+	require.NoError(t, err)
+
+	getter := &testDictionaryGetter{
+		sc:         sc,
+		candidates: spellchecker.NewCandidates(),
+		options: spellchecker.Options{
+			Candidates: &spellchecker.CandidatesOptions{AutoApprove: autoApprove},
+		},
+	}
+
+	var out DictionaryFixResponse
+	err = dictionaryFix(getter, testSplitter, true).Interact(context.Background(), DictionaryFixRequest{Code: "en", Text: text}, &out)
+	require.NoError(t, err)
+
+	return getter
+}
+
+func Test_DictionaryCandidateList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		getter := newTestCandidatesGetter(t, 0, "foo bar foo")
+
+		var out DictionaryCandidateListResponse
+		err := dictionaryCandidateList(getter).Interact(context.Background(), DictionaryCandidateListRequest{Code: "en"}, &out)
+		require.NoError(t, err)
+
+		require.Len(t, out.Items, 2)
+		require.Equal(t, "foo", out.Items[0].Word)
+		require.Equal(t, uint(2), out.Items[0].Count)
+		require.Equal(t, []string{"foo bar foo", "foo bar foo"}, out.Items[0].Contexts)
+		require.Equal(t, "bar", out.Items[1].Word)
+		require.Equal(t, uint(1), out.Items[1].Count)
+	})
+
+	t.Run("auto approve", func(t *testing.T) {
+		t.Parallel()
+
+		getter := newTestCandidatesGetter(t, 2, "foo bar foo")
+
+		var out DictionaryCandidateListResponse
+		err := dictionaryCandidateList(getter).Interact(context.Background(), DictionaryCandidateListRequest{Code: "en"}, &out)
+		require.NoError(t, err)
+
+		require.Len(t, out.Items, 1)
+		require.Equal(t, "bar", out.Items[0].Word)
+		require.True(t, getter.sc.IsCorrect("foo"))
+	})
+
+	t.Run("collection disabled", func(t *testing.T) {
+		t.Parallel()
+
+		getter := newTestCandidatesGetter(t, 0, "")
+
+		var fix DictionaryFixResponse
+		err := dictionaryFix(getter, testSplitter, false).Interact(context.Background(), DictionaryFixRequest{Code: "en", Text: "foo"}, &fix)
+		require.NoError(t, err)
+		require.Len(t, fix.Fixes, 1)
+
+		var out DictionaryCandidateListResponse
+		err = dictionaryCandidateList(getter).Interact(context.Background(), DictionaryCandidateListRequest{Code: "en"}, &out)
+		require.NoError(t, err)
+		require.Empty(t, out.Items)
+	})
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		wantCode status.Code
+	}{
+		{
+			name:     "disabled",
+			getter:   &testDictionaryGetter{},
+			wantCode: status.FailedPrecondition,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryCandidateListResponse
+			err := dictionaryCandidateList(tt.getter).Interact(context.Background(), DictionaryCandidateListRequest{Code: "en"}, &out)
+			require.Error(t, err)
+			require.True(t, err.(isErr).Is(tt.wantCode))
+		})
+	}
+}
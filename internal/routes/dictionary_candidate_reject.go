@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryCandidateRejectRequest struct {
+	Code string `path:"code" minLength:"1"`
+	Word string `path:"word" minLength:"1"`
+}
+
+func dictionaryCandidateReject(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryCandidateRejectRequest, output *Empty) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		err = item.RejectCandidate(input.Word)
+		if errors.Is(err, spellchecker.ErrCandidatesDisabled) {
+			return status.Wrap(err, status.FailedPrecondition)
+		} else if errors.Is(err, spellchecker.ErrCandidateNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Reject a candidate word")
+	u.SetDescription("Removes the candidate word from the candidates list. The word will not be collected anymore.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.FailedPrecondition)
+
+	return u
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryCandidateReject(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		getter := newTestCandidatesGetter(t, 0, "foo")
+
+		var out Empty
+		err := dictionaryCandidateReject(getter).Interact(context.Background(), DictionaryCandidateRejectRequest{Code: "en", Word: "foo"}, &out)
+		require.NoError(t, err)
+		require.False(t, getter.sc.IsCorrect("foo"))
+
+		// rejected words are not collected anymore
+		var fix DictionaryFixResponse
+		err = dictionaryFix(getter, testSplitter, true).Interact(context.Background(), DictionaryFixRequest{Code: "en", Text: "foo"}, &fix)
+		require.NoError(t, err)
+
+		var list DictionaryCandidateListResponse
+		err = dictionaryCandidateList(getter).Interact(context.Background(), DictionaryCandidateListRequest{Code: "en"}, &list)
+		require.NoError(t, err)
+		require.Empty(t, list.Items)
+	})
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		wantCode status.Code
+	}{
+		{
+			name:     "candidate not found",
+			getter:   newTestCandidatesGetter(t, 0, "bar"),
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "disabled",
+			getter:   &testDictionaryGetter{},
+			wantCode: status.FailedPrecondition,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryCandidateReject(tt.getter).Interact(context.Background(), DictionaryCandidateRejectRequest{Code: "en", Word: "foo"}, &out)
+			require.Error(t, err)
+			require.True(t, err.(isErr).Is(tt.wantCode))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/compound"
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
 	"github.com/swaggest/usecase"
 	"github.com/swaggest/usecase/status"
@@ -19,16 +20,56 @@ type DictionaryCreateRequest struct {
 
 	Alphabet  string `json:"alphabet" minLength:"1"`
 	MaxErrors uint   `json:"maxErrors" minimum:"0" maximum:"5"`
+	Phonetic  string `json:"phonetic,omitempty" enum:"metaphone,soundex" description:"Enables the phonetic index used as an additional source of suggestions. Only latin letters are supported by the phonetic algorithms."`
+
+	Compound *DictionaryCompoundOptions `json:"compound,omitempty" description:"Enables compound words handling (e.g. for German). Unknown words which can be split into known parts are considered correct."`
+
+	MinScore  float64 `json:"minScore,omitempty" minimum:"0" description:"Default min score of a suggestion, suggestions with a lower score are dropped."`
+	MinMargin float64 `json:"minMargin,omitempty" minimum:"0" description:"Default min gap between the scores of the first and the second suggestion required for medium or high confidence."`
+
+	Candidates *DictionaryCandidatesOptions `json:"candidates,omitempty" description:"Enables collecting unknown words found by fix requests as candidates to be added to the dictionary."`
+
+	Language    string            `json:"language,omitempty" description:"BCP 47 language tag of the dictionary (e.g. en, de-CH). Used to filter dictionaries in the list and when fixing a text without a dictionary code."`
+	Description string            `json:"description,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" description:"Arbitrary key-value pairs used to filter dictionaries in the list."`
+}
+
+type DictionaryCandidatesOptions struct {
+	AutoApprove uint `json:"autoApprove,omitempty" description:"Add a candidate to the dictionary automatically when it is seen this many times. 0 - disabled."`
+}
+
+type DictionaryCompoundOptions struct {
+	MinPartLength    uint     `json:"minPartLength" default:"3" minimum:"1" description:"Min length of a compound part."`
+	LinkingMorphemes []string `json:"linkingMorphemes,omitempty" description:"Morphemes allowed between compound parts, e.g. s, es."`
 }
 
 func dictionaryCreate(registry registryAdder) usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryCreateRequest, output *Empty) error {
-		_, err := registry.Add(input.Code, spellchecker.Options{
-			Alphabet:  input.Alphabet,
-			MaxErrors: input.MaxErrors,
-		})
+		options := spellchecker.Options{
+			Alphabet:    input.Alphabet,
+			MaxErrors:   input.MaxErrors,
+			Phonetic:    input.Phonetic,
+			Compound:    compoundOptions(input.Compound),
+			MinScore:    input.MinScore,
+			MinMargin:   input.MinMargin,
+			Language:    input.Language,
+			Description: input.Description,
+			Owner:       input.Owner,
+			Labels:      input.Labels,
+		}
+
+		if input.Candidates != nil {
+			options.Candidates = &spellchecker.CandidatesOptions{
+				AutoApprove: input.Candidates.AutoApprove,
+			}
+		}
+
+		_, err := registry.Add(input.Code, options)
 		if errors.Is(spellchecker.ErrAlreadyExists, err) {
 			return status.Wrap(err, status.AlreadyExists)
+		} else if errors.Is(err, spellchecker.ErrInvalidOptions) {
+			return status.Wrap(err, status.InvalidArgument)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
@@ -42,3 +83,14 @@ func dictionaryCreate(registry registryAdder) usecase.Interactor {
 
 	return u
 }
+
+func compoundOptions(input *DictionaryCompoundOptions) *compound.Options {
+	if input == nil {
+		return nil
+	}
+
+	return &compound.Options{
+		MinPartLength:    input.MinPartLength,
+		LinkingMorphemes: input.LinkingMorphemes,
+	}
+}
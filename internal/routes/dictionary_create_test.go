@@ -3,6 +3,7 @@ package routes
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	f1mspellchecker "github.com/f1monkey/spellchecker"
@@ -55,6 +56,19 @@ func Test_DictionaryCreate(t *testing.T) {
 			wantErr:  true,
 			wantCode: status.AlreadyExists,
 		},
+		{
+			name: "invalid code",
+			adder: &testRegistryAdder{
+				err: fmt.Errorf("%w: code %q cannot be used as a file name", spellchecker.ErrInvalidOptions, ".."),
+			},
+			input: DictionaryCreateRequest{
+				Code:      "..",
+				Alphabet:  "abcdefghijklmnopqrstuvwxyz",
+				MaxErrors: 2,
+			},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
 		{
 			name: "internal error",
 			adder: &testRegistryAdder{
@@ -10,18 +10,21 @@ import (
 )
 
 type dictionaryDeleter interface {
-	Delete(code string) error
+	Delete(code string, cascade bool) error
 }
 
 type DictionaryDeleteRequest struct {
-	Code string `path:"code" minLength:"1"`
+	Code    string `path:"code" minLength:"1"`
+	Cascade bool   `query:"cascade" description:"Remove the dictionary from the targets of its aliases too, the aliases left without targets are removed. A dictionary with aliases can not be deleted otherwise."`
 }
 
 func dictionaryDelete(registry dictionaryDeleter) usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryDeleteRequest, output *Empty) error {
-		err := registry.Delete(input.Code)
+		err := registry.Delete(input.Code, input.Cascade)
 		if errors.Is(spellchecker.ErrNotFound, err) {
 			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrHasAliases) {
+			return status.Wrap(err, status.FailedPrecondition)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
@@ -31,7 +34,7 @@ func dictionaryDelete(registry dictionaryDeleter) usecase.Interactor {
 
 	u.SetTitle("Delete a dictionary")
 	u.SetDescription("Removes a dictionary from the registry")
-	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument)
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument, status.FailedPrecondition)
 
 	return u
 }
@@ -15,10 +15,12 @@ type isErr interface {
 }
 
 type testDictionaryDeleter struct {
-	err error
+	cascade bool
+	err     error
 }
 
-func (f *testDictionaryDeleter) Delete(code string) error {
+func (f *testDictionaryDeleter) Delete(code string, cascade bool) error {
+	f.cascade = cascade
 	return f.err
 }
 
@@ -39,6 +41,20 @@ func Test_DictionaryDelete(t *testing.T) {
 			wantErr:  false,
 			wantCode: status.OK,
 		},
+		{
+			name:     "cascade",
+			deleter:  &testDictionaryDeleter{err: nil},
+			input:    DictionaryDeleteRequest{Code: "en", Cascade: true},
+			wantErr:  false,
+			wantCode: status.OK,
+		},
+		{
+			name:     "has aliases",
+			deleter:  &testDictionaryDeleter{err: spellchecker.ErrHasAliases},
+			input:    DictionaryDeleteRequest{Code: "en"},
+			wantErr:  true,
+			wantCode: status.FailedPrecondition,
+		},
 		{
 			name:     "not found",
 			deleter:  &testDictionaryDeleter{err: spellchecker.ErrNotFound},
@@ -63,6 +79,7 @@ func Test_DictionaryDelete(t *testing.T) {
 
 			var out Empty
 			err := interactor.Interact(context.Background(), tt.input, &out)
+			require.Equal(t, tt.input.Cascade, tt.deleter.cascade)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type failedLister interface {
+	Failed() []spellchecker.FailedDictionary
+}
+
+type DictionaryFailedResponse struct {
+	Items []FailedDictionary `json:"items"`
+}
+
+type FailedDictionary struct {
+	Code        string    `json:"code"`
+	Error       string    `json:"error" description:"Load error."`
+	Time        time.Time `json:"time" description:"Time of the failure."`
+	Quarantined bool      `json:"quarantined" description:"The file is moved to the quarantine directory, so it is not loaded on startup anymore. Move the fixed file back to load it again."`
+}
+
+func dictionaryFailed(registry failedLister) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *DictionaryFailedResponse) error {
+		failed := registry.Failed()
+
+		output.Items = make([]FailedDictionary, 0, len(failed))
+		for _, f := range failed {
+			output.Items = append(output.Items, FailedDictionary{
+				Code:        f.Code,
+				Error:       f.Error,
+				Time:        f.Time,
+				Quarantined: f.Quarantined,
+			})
+		}
+
+		return nil
+	})
+
+	u.SetTitle("List dictionaries failed to load")
+	u.SetDescription("Returns dictionaries which files could not be loaded. Corrupted files are moved to the quarantine directory along with the error reports.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
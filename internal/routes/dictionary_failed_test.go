@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+)
+
+type testFailedLister struct {
+	items []spellchecker.FailedDictionary
+}
+
+func (f *testFailedLister) Failed() []spellchecker.FailedDictionary {
+	return f.items
+}
+
+func Test_DictionaryFailed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		lister *testFailedLister
+		want   []FailedDictionary
+	}{
+		{
+			name:   "empty",
+			lister: &testFailedLister{},
+			want:   []FailedDictionary{},
+		},
+		{
+			name: "failed",
+			lister: &testFailedLister{items: []spellchecker.FailedDictionary{
+				{Code: "en", Error: "invalid character", Time: now, Quarantined: true},
+			}},
+			want: []FailedDictionary{
+				{Code: "en", Error: "invalid character", Time: now, Quarantined: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryFailedResponse
+			err := dictionaryFailed(tt.lister).Interact(context.Background(), Empty{}, &out)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out.Items)
+		})
+	}
+}
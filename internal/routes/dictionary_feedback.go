@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+const (
+	feedbackAccepted = "accepted"
+	feedbackRejected = "rejected"
+	feedbackIgnored  = "ignored"
+)
+
+type DictionaryFeedbackRequest struct {
+	Code string `path:"code" minLength:"1"`
+
+	Events []FeedbackEvent `json:"events" minItems:"1"`
+}
+
+type FeedbackEvent struct {
+	Type       string `json:"type" enum:"accepted,rejected,ignored" description:"accepted - the suggestion was applied by a user; rejected - the suggestion was declined by a user; ignored - the word was marked as correct by a user."`
+	Word       string `json:"word" minLength:"1" description:"The word as it was written in the checked text."`
+	Suggestion string `json:"suggestion,omitempty" description:"The suggestion that was accepted or rejected. Required for accepted and rejected events."`
+	Weight     uint   `json:"weight" min:"1" description:"Weight added to the accepted suggestion or to the ignored word."`
+}
+
+type DictionaryFeedbackResponse struct {
+	Events int `json:"events" description:"Number of events recorded."`
+}
+
+var errInvalidFeedback = fmt.Errorf("invalid feedback event")
+
+func dictionaryFeedback(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryFeedbackRequest, output *DictionaryFeedbackResponse) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		for i, e := range input.Events {
+			if e.Word == "" {
+				return status.Wrap(fmt.Errorf("%w #%d: empty word", errInvalidFeedback, i), status.InvalidArgument)
+			}
+
+			switch e.Type {
+			case feedbackAccepted, feedbackRejected:
+				if e.Suggestion == "" {
+					return status.Wrap(fmt.Errorf("%w #%d: suggestion is required", errInvalidFeedback, i), status.InvalidArgument)
+				}
+			case feedbackIgnored:
+			default:
+				return status.Wrap(fmt.Errorf("%w #%d: unknown type %q", errInvalidFeedback, i, e.Type), status.InvalidArgument)
+			}
+		}
+
+		for _, e := range input.Events {
+			weight := e.Weight
+			if weight == 0 {
+				weight = 1
+			}
+
+			switch e.Type {
+			case feedbackAccepted:
+				item.Accept(e.Word, e.Suggestion, weight)
+			case feedbackRejected:
+				item.Reject(e.Word, e.Suggestion)
+			case feedbackIgnored:
+				item.Ignore(e.Word, weight)
+			}
+		}
+
+		output.Events = len(input.Events)
+
+		return nil
+	})
+
+	u.SetTitle("Send feedback on suggestions")
+	u.SetDescription("Records accepted and rejected suggestions and ignored words. An accepted suggestion gets additional weight and becomes the preferred fix for the word, a rejected one is no longer suggested for the word, an ignored word is added to the dictionary.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument)
+
+	return u
+}
@@ -0,0 +1,134 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryFeedback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		getter     *testDictionaryGetter
+		input      DictionaryFeedbackRequest
+		wantErr    bool
+		wantCode   status.Code
+		wantEvents int
+		check      func(t *testing.T, item spellchecker.RegistryItem)
+	}{
+		{
+			name:   "accepted",
+			getter: &testDictionaryGetter{},
+			input: DictionaryFeedbackRequest{
+				Code:   "en",
+				Events: []FeedbackEvent{{Type: "accepted", Word: "waapon", Suggestion: "weapon"}},
+			},
+			wantEvents: 1,
+			check: func(t *testing.T, item spellchecker.RegistryItem) {
+				v, ok := item.Feedback.Correction("waapon")
+				require.True(t, ok)
+				require.Equal(t, "weapon", v)
+				require.True(t, item.Spellchecker.IsCorrect("weapon"))
+			},
+		},
+		{
+			name:   "rejected",
+			getter: &testDictionaryGetter{},
+			input: DictionaryFeedbackRequest{
+				Code: "en",
+				Events: []FeedbackEvent{
+					{Type: "accepted", Word: "waapon", Suggestion: "weapon"},
+					{Type: "rejected", Word: "waapon", Suggestion: "weapon"},
+				},
+			},
+			wantEvents: 2,
+			check: func(t *testing.T, item spellchecker.RegistryItem) {
+				_, ok := item.Feedback.Correction("waapon")
+				require.False(t, ok)
+				require.True(t, item.Feedback.IsRejected("waapon", "weapon"))
+			},
+		},
+		{
+			name:   "ignored",
+			getter: &testDictionaryGetter{},
+			input: DictionaryFeedbackRequest{
+				Code:   "en",
+				Events: []FeedbackEvent{{Type: "ignored", Word: "frobnicate"}},
+			},
+			wantEvents: 1,
+			check: func(t *testing.T, item spellchecker.RegistryItem) {
+				require.True(t, item.Spellchecker.IsCorrect("frobnicate"))
+			},
+		},
+		{
+			name:   "suggestion is required",
+			getter: &testDictionaryGetter{},
+			input: DictionaryFeedbackRequest{
+				Code:   "en",
+				Events: []FeedbackEvent{{Type: "accepted", Word: "waapon"}},
+			},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:   "unknown type",
+			getter: &testDictionaryGetter{},
+			input: DictionaryFeedbackRequest{
+				Code:   "en",
+				Events: []FeedbackEvent{{Type: "qwerty", Word: "waapon"}},
+			},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			input:    DictionaryFeedbackRequest{Code: "xx"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			input:    DictionaryFeedbackRequest{Code: "en"},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc, err := f1mspellchecker.New(f1mspellchecker.DefaultAlphabet)
+			require.NoError(t, err)
+
+			tt.getter.sc = sc
+			tt.getter.feedback = spellchecker.NewFeedback()
+
+			interactor := dictionaryFeedback(tt.getter)
+
+			var out DictionaryFeedbackResponse
+			err = interactor.Interact(context.Background(), tt.input, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantEvents, out.Events)
+
+				item, err := tt.getter.GetItem(tt.input.Code)
+				require.NoError(t, err)
+				tt.check(t, item)
+			}
+		})
+	}
+}
@@ -4,35 +4,50 @@ import (
 	"context"
 	"errors"
 	"regexp"
-	"unicode/utf8"
 
-	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/layout"
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/tokenizer"
 	"github.com/swaggest/usecase"
 	"github.com/swaggest/usecase/status"
 )
 
 type dictionaryGetter interface {
-	Get(code string) (*f1mspellchecker.Spellchecker, error)
+	GetItem(code string) (spellchecker.RegistryItem, error)
+}
+
+type dictionaryResolver interface {
+	dictionaryGetter
+	ResolveItem(name string, key string) (string, spellchecker.RegistryItem, error)
 }
 
 type DictionaryFixRequest struct {
-	Code string `path:"code" minLength:"1"`
+	Code       string `path:"code" minLength:"1"`
+	RoutingKey string `header:"X-Routing-Key" description:"Requests with the same key are always routed to the same dictionary of an alias with several targets (e.g. a user id). A random dictionary is chosen by weights if it is empty."`
 
 	Text  string `json:"text" description:"Phrase to be checked"`
 	Limit int    `json:"limit" default:"5" desciption:"Max suggestions per word"`
+
+	Layouts          []string `json:"layouts,omitempty" items.enum:"en-ru" description:"Keyboard layout pairs used to detect words typed with a wrong layout (e.g. ghbdtn => привет)."`
+	LayoutDictionary string   `json:"layoutDictionary,omitempty" description:"Dictionary used to check words converted to another layout. The requested dictionary is used by default."`
+
+	MinScore  *float64 `json:"minScore,omitempty" minimum:"0" description:"Suggestions with a lower score are dropped. Overrides the dictionary setting."`
+	MinMargin *float64 `json:"minMargin,omitempty" minimum:"0" description:"Min gap between the scores of the first and the second suggestion required for medium or high confidence. Overrides the dictionary setting."`
 }
 
 type DictionaryFixResponse struct {
-	Fixes   []Fix     `json:"fixes" description:"List of detected issues."`
-	Correct []Correct `json:"correct" description:"List of correct words."`
+	Dictionary string    `json:"dictionary" description:"Code of the dictionary which served the request (the requested one or the one an alias resolved to)."`
+	Fixes      []Fix     `json:"fixes" description:"List of detected issues."`
+	Correct    []Correct `json:"correct" description:"List of correct words."`
 }
 
 type Fix struct {
 	Start       int                      `json:"start" description:"Starting character index of the incorrect word in the input."`
 	End         int                      `json:"end" description:"Ending character index."`
 	Suggestions []SpellcheckerSuggestion `json:"suggestions,omitempty" description:"List of correction suggestions."`
-	Error       string                   `json:"error" enum:"unknown_word,invalid_word" description:"Type of detected error. unknown_word - no possible corrections found; invalid_word - the word can be corrected using one of the provided suggestions"`
+	Confidence  string                   `json:"confidence" enum:"high,medium,low" description:"Confidence of the first suggestion. low - the suggestions are ambiguous (the gap between the first and the second one is less than minMargin) or there are no suggestions at all; medium and high - depend on the gap relative to the score of the first suggestion. Only high confidence fixes are recommended to be applied automatically."`
+	Dictionary  string                   `json:"dictionary,omitempty" description:"Dictionary used to check the word (only for the text fixed without a dictionary code)."`
+	Error       string                   `json:"error" enum:"unknown_word,invalid_word,wrong_layout,replacement,forbidden_word" description:"Type of detected error. unknown_word - no possible corrections found (or all of them have a score less than minScore); invalid_word - the word can be corrected using one of the provided suggestions; wrong_layout - the word was typed using a wrong keyboard layout, the suggestion contains the converted text; replacement - the text matches a static correction rule of the dictionary, the suggestion contains the replacement; forbidden_word - the word must not be used"`
 }
 
 type Correct struct {
@@ -41,82 +56,87 @@ type Correct struct {
 }
 
 type SpellcheckerSuggestion struct {
-	Text  string  `json:"text" descrption:"Suggested corrected word."`
-	Score float64 `json:"score" description:"Confidence score of the suggestion."`
+	Text   string  `json:"text" descrption:"Suggested corrected word."`
+	Score  float64 `json:"score" description:"Confidence score of the suggestion."`
+	Source string  `json:"source" enum:"edit_distance,phonetic,layout,feedback,rule" description:"Source of the suggestion. edit_distance - words within the max errors distance; phonetic - words which sound alike (if the phonetic index is enabled for the dictionary); layout - the word converted to another keyboard layout; feedback - the correction learned from the users' feedback; rule - the replacement of a static correction rule"`
 }
 
-func dictionaryFix(registry dictionaryGetter, splitter *regexp.Regexp) usecase.Interactor {
-	const (
-		errorUnknownWord = "unknown_word"
-		errorInvalidWord = "invalid_word"
-	)
+// dictionaryFix checks the text, unknown words are collected as candidates if collect is true
+func dictionaryFix(registry dictionaryResolver, splitter *regexp.Regexp, collect bool) usecase.Interactor {
+	tokens := tokenizer.New(splitter)
 
 	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryFixRequest, output *DictionaryFixResponse) error {
-		sc, err := registry.Get(input.Code)
+		code, item, err := registry.ResolveItem(input.Code, input.RoutingKey)
 		if errors.Is(spellchecker.ErrNotFound, err) {
 			return status.Wrap(err, status.NotFound)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
+		defer item.Release()
 
-		if input.Text == "" {
-			output.Fixes = make([]Fix, 0)
-			return nil
+		output.Dictionary = code
+
+		f := &fixer{
+			item:      item,
+			layoutSc:  item.Spellchecker,
+			collect:   collect,
+			limit:     input.Limit,
+			minScore:  item.Options.MinScore,
+			minMargin: item.Options.MinMargin,
 		}
 
-		matches := splitter.FindAllStringIndex(input.Text, -1)
-		fixes := make([]Fix, 0, len(matches))
-		correct := make([]Correct, 0, len(matches))
+		if input.MinScore != nil {
+			f.minScore = *input.MinScore
+		}
 
-		for _, match := range matches {
-			startByte, endByte := match[0], match[1]
-			startRune := utf8.RuneCountInString(input.Text[:startByte])
-			endRune := startRune + utf8.RuneCountInString(input.Text[startByte:endByte])
+		if input.MinMargin != nil {
+			f.minMargin = *input.MinMargin
+		}
 
-			fix := Fix{
-				Start: startRune,
-				End:   endRune,
+		for _, name := range input.Layouts {
+			p, err := layout.Get(name)
+			if err != nil {
+				return status.Wrap(err, status.InvalidArgument)
 			}
 
-			word := input.Text[startByte:endByte]
+			f.layouts = append(f.layouts, p)
+		}
 
-			suggestions := sc.SuggestScore(word, input.Limit)
+		if len(f.layouts) > 0 && input.LayoutDictionary != "" {
+			layoutItem, err := registry.GetItem(input.LayoutDictionary)
+			if errors.Is(spellchecker.ErrNotFound, err) {
+				return status.Wrap(err, status.NotFound)
+			} else if err != nil {
+				return status.Wrap(err, status.Internal)
+			}
+			defer layoutItem.Release()
 
-			if suggestions.ExactMatch {
-				correct = append(correct, Correct{
-					Start: startRune,
-					End:   endRune,
-				})
+			f.layoutSc = layoutItem.Spellchecker
+		}
 
-				continue
-			}
+		if input.Text == "" {
+			output.Fixes = make([]Fix, 0)
+			return nil
+		}
 
-			if len(suggestions.Suggestions) == 0 {
-				fix.Error = errorUnknownWord
-			} else {
-				fix.Error = errorInvalidWord
-				fix.Suggestions = make([]SpellcheckerSuggestion, 0, len(suggestions.Suggestions))
-
-				for _, s := range suggestions.Suggestions {
-					fix.Suggestions = append(fix.Suggestions, SpellcheckerSuggestion{
-						Text:  s.Value,
-						Score: s.Score,
-					})
-				}
+		words := tokens.Tokens(input.Text)
+		if len(f.layouts) > 0 {
+			var letters []rune
+			for _, p := range f.layouts {
+				letters = append(letters, p.Letters()...)
 			}
 
-			fixes = append(fixes, fix)
+			words = f.layoutTokens(words, tokens.WithLetters(letters).Tokens(input.Text))
 		}
 
-		output.Fixes = fixes
-		output.Correct = correct
+		output.Fixes, output.Correct = f.fix(input.Text, words)
 
 		return nil
 	})
 
 	u.SetTitle("Fix text")
 	u.SetDescription("Performs spellchecking on the given input text. Returns misspelled words along with suggested corrections, up to the specified limit per word.")
-	u.SetExpectedErrors(status.Internal, status.NotFound)
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument)
 
 	return u
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/compound"
+	"github.com/f1monkey/spellchecker-web/internal/phonetic"
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,16 +16,38 @@ import (
 )
 
 type testDictionaryGetter struct {
-	sc  *f1mspellchecker.Spellchecker
-	err error
+	sc         *f1mspellchecker.Spellchecker
+	phonetic   *phonetic.Index
+	feedback   *spellchecker.Feedback
+	candidates *spellchecker.Candidates
+	rules      *spellchecker.Rules
+	options    spellchecker.Options
+	err        error
 }
 
-func (f *testDictionaryGetter) Get(code string) (*f1mspellchecker.Spellchecker, error) {
+func (f *testDictionaryGetter) GetItem(code string) (spellchecker.RegistryItem, error) {
 	if f.err != nil {
-		return nil, f.err
+		return spellchecker.RegistryItem{}, f.err
 	}
 
-	return f.sc, nil
+	return spellchecker.RegistryItem{
+		Spellchecker: f.sc,
+		Phonetic:     f.phonetic,
+		Feedback:     f.feedback,
+		Candidates:   f.candidates,
+		Rules:        f.rules,
+		Options:      f.options,
+	}, nil
+}
+
+func (f *testDictionaryGetter) ResolveItem(name string, key string) (string, spellchecker.RegistryItem, error) {
+	item, err := f.GetItem(name)
+
+	return name, item, err
+}
+
+func ptr[T any](v T) *T {
+	return &v
 }
 
 func Test_DictionaryFix(t *testing.T) {
@@ -34,11 +58,33 @@ func Test_DictionaryFix(t *testing.T) {
 	sc, err := f1mspellchecker.New(f1mspellchecker.DefaultAlphabet)
 	require.NoError(t, err)
 
-	sc.Add("hello")
+	sc.Add("hello", "привет", "можно", "well-known")
+
+	phoneticSc, err := f1mspellchecker.New(f1mspellchecker.DefaultAlphabet)
+	require.NoError(t, err)
+
+	phoneticIdx, err := phonetic.NewIndex(phonetic.AlgorithmMetaphone)
+	require.NoError(t, err)
+
+	spellchecker.RegistryItem{Spellchecker: phoneticSc, Phonetic: phoneticIdx}.AddWeight(1, "phonetic")
+
+	feedback := spellchecker.NewFeedback()
+	spellchecker.RegistryItem{Spellchecker: sc, Feedback: feedback}.Accept("helo", "hello", 1)
+	spellchecker.RegistryItem{Spellchecker: sc, Feedback: feedback}.Reject("hellp", "hello")
+
+	rules := spellchecker.NewRules()
+	require.NoError(t, rules.SetRule(spellchecker.Rule{ID: "alot", Pattern: "alot", Replacement: "a lot"}))
+	rules.Forbid("hello")
+
+	compoundSc, err := f1mspellchecker.New(f1mspellchecker.DefaultAlphabet + "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	require.NoError(t, err)
+
+	compoundSc.Add("Donau", "Dampf", "Schiff", "Fahrt")
+	compoundOpts := spellchecker.Options{Compound: &compound.Options{MinPartLength: 3}}
 
 	tests := []struct {
 		name        string
-		getter      dictionaryGetter
+		getter      dictionaryResolver
 		input       DictionaryFixRequest
 		wantErr     bool
 		wantCode    status.Code
@@ -83,6 +129,118 @@ func Test_DictionaryFix(t *testing.T) {
 			},
 			wantCorrect: []Correct{},
 		},
+		{
+			name: "zero limit",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "hellp", Limit: 0},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 5,
+					Error:      "invalid_word",
+					Confidence: "high",
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "suggestions below min score",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "hellp", Limit: 5, MinScore: ptr(100.0)},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 5,
+					Error:      "unknown_word",
+					Confidence: "low",
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "single suggestion has high confidence",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "hellp", Limit: 5, MinMargin: ptr(0.1)},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 5,
+					Error:      "invalid_word",
+					Confidence: "high",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "hello"},
+					},
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "learned correction",
+			getter: &testDictionaryGetter{
+				sc:       sc,
+				feedback: feedback,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "helo", Limit: 5},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 4,
+					Error:      "invalid_word",
+					Confidence: "high",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "hello", Source: "feedback"},
+					},
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "rejected suggestion",
+			getter: &testDictionaryGetter{
+				sc:       sc,
+				feedback: feedback,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "hellp", Limit: 5},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 5,
+					Error: "unknown_word",
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "rules and forbidden words",
+			getter: &testDictionaryGetter{
+				sc:    sc,
+				rules: rules,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "hello alot", Limit: 5},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 5,
+					Error:      "forbidden_word",
+					Confidence: "high",
+				},
+				{
+					Start: 6, End: 10,
+					Error:      "replacement",
+					Confidence: "high",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "a lot", Source: "rule"},
+					},
+				},
+			},
+			wantCorrect: []Correct{},
+		},
 		{
 			name: "word without suggestions",
 			getter: &testDictionaryGetter{
@@ -98,6 +256,137 @@ func Test_DictionaryFix(t *testing.T) {
 			},
 			wantCorrect: []Correct{},
 		},
+		{
+			name: "phonetic suggestions",
+			getter: &testDictionaryGetter{
+				sc:       phoneticSc,
+				phonetic: phoneticIdx,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "fonetik", Limit: 5},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 7,
+					Error: "invalid_word",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "phonetic", Source: "phonetic"},
+					},
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "compound word",
+			getter: &testDictionaryGetter{
+				sc:      compoundSc,
+				options: compoundOpts,
+			},
+			input:     DictionaryFixRequest{Code: "de", Text: "die Donaudampfschifffahrt", Limit: 5},
+			wantErr:   false,
+			wantFixes: []Fix{{Start: 0, End: 3, Error: "unknown_word"}},
+			wantCorrect: []Correct{
+				{Start: 4, End: 25},
+			},
+		},
+		{
+			name: "compound word with a misspelled part",
+			getter: &testDictionaryGetter{
+				sc:      compoundSc,
+				options: compoundOpts,
+			},
+			input:   DictionaryFixRequest{Code: "de", Text: "die Donaudampfschiffahrt", Limit: 5},
+			wantErr: false,
+			wantFixes: []Fix{
+				{Start: 0, End: 3, Error: "unknown_word"},
+				{
+					Start: 20, End: 24,
+					Error: "invalid_word",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "fahrt"},
+					},
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "hyphenated word known as a whole",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:     DictionaryFixRequest{Code: "en", Text: "well-known", Limit: 5},
+			wantErr:   false,
+			wantFixes: []Fix{},
+			wantCorrect: []Correct{
+				{Start: 0, End: 10},
+			},
+		},
+		{
+			name: "hyphenated word checked part-wise",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "hello-hellp", Limit: 5},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 6, End: 11,
+					Error: "invalid_word",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "hello"},
+					},
+				},
+			},
+			wantCorrect: []Correct{
+				{Start: 0, End: 5},
+			},
+		},
+		{
+			name: "wrong layout",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "ghbdtn", Limit: 5, Layouts: []string{"en-ru"}},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 0, End: 6,
+					Error: "wrong_layout",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "привет"},
+					},
+				},
+			},
+			wantCorrect: []Correct{},
+		},
+		{
+			name: "wrong layout with punctuation keys",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:   DictionaryFixRequest{Code: "en", Text: "'hello' vj;yj", Limit: 5, Layouts: []string{"en-ru"}},
+			wantErr: false,
+			wantFixes: []Fix{
+				{
+					Start: 8, End: 13,
+					Error: "wrong_layout",
+					Suggestions: []SpellcheckerSuggestion{
+						{Text: "можно"},
+					},
+				},
+			},
+			wantCorrect: []Correct{
+				{Start: 1, End: 6},
+			},
+		},
+		{
+			name: "unknown layout",
+			getter: &testDictionaryGetter{
+				sc: sc,
+			},
+			input:    DictionaryFixRequest{Code: "en", Text: "ghbdtn", Limit: 5, Layouts: []string{"xx-yy"}},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
 		{
 			name:     "dictionary not found",
 			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
@@ -118,7 +407,7 @@ func Test_DictionaryFix(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			interactor := dictionaryFix(tt.getter, splitter)
+			interactor := dictionaryFix(tt.getter, splitter, true)
 
 			var out DictionaryFixResponse
 			err := interactor.Interact(context.Background(), tt.input, &out)
@@ -128,6 +417,7 @@ func Test_DictionaryFix(t *testing.T) {
 				require.True(t, err.(isErr).Is(tt.wantCode))
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, tt.input.Code, out.Dictionary)
 
 				require.Len(t, out.Fixes, len(tt.wantFixes))
 
@@ -136,12 +426,16 @@ func Test_DictionaryFix(t *testing.T) {
 					require.Len(t, out.Fixes[i].Suggestions, len(f.Suggestions))
 
 					for j, s := range f.Suggestions {
-						assert.Equal(t, s.Text, tt.wantFixes[i].Suggestions[j].Text)
+						assert.Equal(t, s.Text, out.Fixes[i].Suggestions[j].Text)
 					}
 
 					assert.Equal(t, f.Start, out.Fixes[i].Start)
 					assert.Equal(t, f.End, out.Fixes[i].End)
 					assert.Equal(t, f.Error, out.Fixes[i].Error)
+
+					if f.Confidence != "" {
+						assert.Equal(t, f.Confidence, out.Fixes[i].Confidence)
+					}
 				}
 
 				require.Len(t, out.Correct, len(tt.wantCorrect))
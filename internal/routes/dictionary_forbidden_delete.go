@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryForbiddenDeleteRequest struct {
+	Code string `path:"code" minLength:"1"`
+	Word string `path:"word" minLength:"1" description:"Word to remove from the forbidden list"`
+}
+
+func dictionaryForbiddenDelete(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryForbiddenDeleteRequest, output *Empty) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		err = item.Rules.Allow(input.Word)
+		if errors.Is(err, spellchecker.ErrForbiddenWordNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Remove a forbidden word")
+	u.SetDescription("Removes a word from the forbidden list of the dictionary")
+	u.SetExpectedErrors(status.Internal, status.NotFound)
+
+	return u
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryForbiddenDelete(t *testing.T) {
+	t.Parallel()
+
+	newRules := func() *spellchecker.Rules {
+		r := spellchecker.NewRules()
+		r.Forbid("irregardless")
+
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		input    DictionaryForbiddenDeleteRequest
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:   "success",
+			getter: &testDictionaryGetter{rules: newRules()},
+			input:  DictionaryForbiddenDeleteRequest{Code: "en", Word: "irregardless"},
+		},
+		{
+			name:     "word not found",
+			getter:   &testDictionaryGetter{rules: newRules()},
+			input:    DictionaryForbiddenDeleteRequest{Code: "en", Word: "qwerty"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			input:    DictionaryForbiddenDeleteRequest{Code: "xx", Word: "irregardless"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			input:    DictionaryForbiddenDeleteRequest{Code: "en", Word: "irregardless"},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryForbiddenDelete(tt.getter).Interact(context.Background(), tt.input, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.False(t, tt.getter.rules.IsForbidden("irregardless"))
+			}
+		})
+	}
+}
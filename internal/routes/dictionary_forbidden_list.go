@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryForbiddenListRequest struct {
+	Code string `path:"code" minLength:"1"`
+}
+
+type DictionaryForbiddenListResponse struct {
+	Items []string `json:"items"`
+}
+
+func dictionaryForbiddenList(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryForbiddenListRequest, output *DictionaryForbiddenListResponse) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		output.Items = item.Rules.ListForbidden()
+		if output.Items == nil {
+			output.Items = make([]string, 0)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("List forbidden words")
+	u.SetDescription("Returns words which are always reported by fix requests")
+	u.SetExpectedErrors(status.Internal, status.NotFound)
+
+	return u
+}
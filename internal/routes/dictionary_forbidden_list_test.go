@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryForbiddenList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		rules := spellchecker.NewRules()
+		rules.Forbid("irregardless")
+
+		var out DictionaryForbiddenListResponse
+		err := dictionaryForbiddenList(&testDictionaryGetter{rules: rules}).Interact(context.Background(), DictionaryForbiddenListRequest{Code: "en"}, &out)
+		require.NoError(t, err)
+		require.Equal(t, []string{"irregardless"}, out.Items)
+	})
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		wantCode status.Code
+	}{
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryForbiddenListResponse
+			err := dictionaryForbiddenList(tt.getter).Interact(context.Background(), DictionaryForbiddenListRequest{Code: "en"}, &out)
+			require.Error(t, err)
+			require.True(t, err.(isErr).Is(tt.wantCode))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryForbiddenSetRequest struct {
+	Code string `path:"code" minLength:"1"`
+	Word string `path:"word" minLength:"1" description:"Word to forbid"`
+}
+
+func dictionaryForbiddenSet(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryForbiddenSetRequest, output *Empty) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		item.Rules.Forbid(input.Word)
+
+		return nil
+	})
+
+	u.SetTitle("Forbid a word")
+	u.SetDescription("Adds a word to the forbidden list of the dictionary. Fix requests report forbidden words with the forbidden_word error even if the dictionary contains them.")
+	u.SetExpectedErrors(status.Internal, status.NotFound)
+
+	return u
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryForbiddenSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:   "success",
+			getter: &testDictionaryGetter{rules: spellchecker.NewRules()},
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryForbiddenSet(tt.getter).Interact(context.Background(), DictionaryForbiddenSetRequest{Code: "en", Word: "irregardless"}, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.True(t, tt.getter.rules.IsForbidden("irregardless"))
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/tokenizer"
 	"github.com/swaggest/usecase"
 	"github.com/swaggest/usecase/status"
 )
@@ -22,34 +23,44 @@ type DictionaryItemPhrase struct {
 }
 
 type DictionaryItemAddResponse struct {
-	Words int `json:"words" description:"Number of phrases successfully added."`
+	Words int `json:"words" description:"Number of words successfully added. A hyphenated word is counted once, though its parts are added too."`
 }
 
 func dictionaryItemAdd(registry dictionaryGetter, splitter *regexp.Regexp) usecase.Interactor {
+	tokens := tokenizer.New(splitter)
+
 	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryItemAddRequest, output *DictionaryItemAddResponse) error {
-		sc, err := registry.Get(input.Code)
+		item, err := registry.GetItem(input.Code)
 		if errors.Is(spellchecker.ErrNotFound, err) {
 			return status.Wrap(err, status.NotFound)
 		} else if err != nil {
 			return status.Wrap(err, status.Internal)
 		}
+		defer item.Release()
 
 		wordCnt := 0
 
 		for i := range input.Phrases {
-
-			words := splitter.FindAllString(input.Phrases[i].Text, -1)
-			if len(words) == 0 {
+			found := tokens.Tokens(input.Phrases[i].Text)
+			if len(found) == 0 {
 				continue
 			}
 
+			words := make([]string, 0, len(found))
+			for _, token := range found {
+				words = append(words, token.Text)
+				for _, p := range token.Parts {
+					words = append(words, p.Text)
+				}
+			}
+
 			weight := input.Phrases[i].Weight
 			if weight == 0 {
 				weight = 1
 			}
 
-			sc.AddWeight(weight, words...)
-			wordCnt += len(words)
+			item.AddWeight(weight, words...)
+			wordCnt += len(found)
 		}
 
 		output.Words = wordCnt
@@ -58,7 +69,7 @@ func dictionaryItemAdd(registry dictionaryGetter, splitter *regexp.Regexp) useca
 	})
 
 	u.SetTitle("Add phrases/words to spellchecker")
-	u.SetDescription("Adds one or more custom phrases or words to the spellchecker dictionary. Each phrase can have an optional weight to influence matching or prioritization.")
+	u.SetDescription("Adds one or more custom phrases or words to the spellchecker dictionary. Each phrase can have an optional weight to influence matching or prioritization. Hyphenated words are added both as a whole and part-wise.")
 	u.SetExpectedErrors(status.Internal)
 
 	return u
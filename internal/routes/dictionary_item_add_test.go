@@ -54,6 +54,41 @@ func Test_DictionaryItemAdd(t *testing.T) {
 			wantAdded:  [][]string{{"hello", "world"}},
 			wantWeight: []uint{2},
 		},
+		{
+			name: "hyphenated word is added as a whole and part-wise",
+			getter: &testDictionaryGetter{
+				err: nil,
+			},
+			input: DictionaryItemAddRequest{
+				Code: "en",
+				Phrases: []DictionaryItemPhrase{
+					{Text: "well-known", Weight: 1},
+				},
+			},
+			wantErr:    false,
+			wantCode:   status.OK,
+			wantWords:  1,
+			wantAdded:  [][]string{{"well-known", "well", "known"}},
+			wantWeight: []uint{1},
+		},
+		{
+			name: "hyphenated word is counted once",
+			getter: &testDictionaryGetter{
+				err: nil,
+			},
+			input: DictionaryItemAddRequest{
+				Code: "en",
+				Phrases: []DictionaryItemPhrase{
+					{Text: "a well-known word", Weight: 1},
+					{Text: "state-of-the-art", Weight: 1},
+				},
+			},
+			wantErr:    false,
+			wantCode:   status.OK,
+			wantWords:  4,
+			wantAdded:  [][]string{{"a", "well-known", "well", "known", "word"}, {"state-of-the-art", "state", "of", "the", "art"}},
+			wantWeight: []uint{1, 1},
+		},
 		{
 			name: "phrase with zero weight gets default=1",
 			getter: &testDictionaryGetter{
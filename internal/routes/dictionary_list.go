@@ -2,6 +2,8 @@ package routes
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
 	"github.com/swaggest/usecase"
@@ -12,6 +14,11 @@ type dictionaryLister interface {
 	List() []spellchecker.ListItem
 }
 
+type DictionaryListRequest struct {
+	Labels []string `query:"label" description:"Only dictionaries having the label: key=value or just key. Multiple labels are combined with AND."`
+	Lang   string   `query:"lang" description:"Only dictionaries with the language tag or its subtags (en matches en and en-US)."`
+}
+
 type DictionaryListResponse struct {
 	Items []ListItem `json:"items"`
 }
@@ -19,19 +26,27 @@ type DictionaryListResponse struct {
 type ListItem struct {
 	Code    string   `json:"code"`
 	Aliases []string `json:"aliases"`
+
+	Language    string            `json:"language,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	CreatedAt   *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time        `json:"updatedAt,omitempty"`
 }
 
 func dictionaryList(registry dictionaryLister) usecase.Interactor {
-	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *DictionaryListResponse) error {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryListRequest, output *DictionaryListResponse) error {
 		items := registry.List()
 
 		result := make([]ListItem, 0, len(items))
 
 		for _, item := range items {
-			result = append(result, ListItem{
-				Code:    item.Code,
-				Aliases: item.Aliases,
-			})
+			if !matchLanguage(item.Options.Language, input.Lang) || !matchLabels(item.Options.Labels, input.Labels) {
+				continue
+			}
+
+			result = append(result, listItem(item))
 		}
 
 		output.Items = result
@@ -40,8 +55,56 @@ func dictionaryList(registry dictionaryLister) usecase.Interactor {
 	})
 
 	u.SetTitle("List all dictionaries")
-	u.SetDescription("With their aliases")
+	u.SetDescription("With their aliases and descriptive fields")
 	u.SetExpectedErrors(status.Internal, status.AlreadyExists, status.InvalidArgument)
 
 	return u
 }
+
+func listItem(item spellchecker.ListItem) ListItem {
+	result := ListItem{
+		Code:        item.Code,
+		Aliases:     item.Aliases,
+		Language:    item.Options.Language,
+		Description: item.Options.Description,
+		Owner:       item.Options.Owner,
+		Labels:      item.Options.Labels,
+	}
+
+	if !item.Options.CreatedAt.IsZero() {
+		result.CreatedAt = &item.Options.CreatedAt
+	}
+
+	if !item.Options.UpdatedAt.IsZero() {
+		result.UpdatedAt = &item.Options.UpdatedAt
+	}
+
+	return result
+}
+
+// matchLanguage checks if the tag equals the filter or is its subtag (en-US for en)
+func matchLanguage(tag string, filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	if len(tag) < len(filter) || !strings.EqualFold(tag[:len(filter)], filter) {
+		return false
+	}
+
+	return len(tag) == len(filter) || tag[len(filter)] == '-'
+}
+
+// matchLabels checks if the labels contain all the filters (key=value or key)
+func matchLabels(labels map[string]string, filters []string) bool {
+	for _, f := range filters {
+		key, value, withValue := strings.Cut(f, "=")
+
+		v, ok := labels[key]
+		if !ok || (withValue && v != value) {
+			return false
+		}
+	}
+
+	return true
+}
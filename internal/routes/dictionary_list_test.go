@@ -3,6 +3,7 @@ package routes
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
 	"github.com/stretchr/testify/require"
@@ -20,9 +21,18 @@ func (f *testDictionaryLister) List() []spellchecker.ListItem {
 func Test_DictionaryList(t *testing.T) {
 	t.Parallel()
 
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	filtered := &testDictionaryLister{items: []spellchecker.ListItem{
+		{Code: "en", Options: spellchecker.Options{Language: "en", Labels: map[string]string{"env": "prod", "team": "search"}}},
+		{Code: "en-us", Options: spellchecker.Options{Language: "en-US", Labels: map[string]string{"env": "dev"}}},
+		{Code: "eo", Options: spellchecker.Options{Language: "eo"}},
+	}}
+
 	tests := []struct {
 		name      string
 		lister    *testDictionaryLister
+		input     DictionaryListRequest
 		wantItems []ListItem
 	}{
 		{
@@ -50,6 +60,53 @@ func Test_DictionaryList(t *testing.T) {
 				{Code: "fr", Aliases: []string{"fra", "french"}},
 			},
 		},
+		{
+			name: "descriptive fields",
+			lister: &testDictionaryLister{items: []spellchecker.ListItem{
+				{Code: "en", Options: spellchecker.Options{
+					Language:    "en",
+					Description: "English",
+					Owner:       "search-team",
+					Labels:      map[string]string{"env": "prod"},
+					CreatedAt:   created,
+					UpdatedAt:   created,
+				}},
+			}},
+			wantItems: []ListItem{
+				{
+					Code:        "en",
+					Language:    "en",
+					Description: "English",
+					Owner:       "search-team",
+					Labels:      map[string]string{"env": "prod"},
+					CreatedAt:   &created,
+					UpdatedAt:   &created,
+				},
+			},
+		},
+		{
+			name:   "filter by language",
+			lister: filtered,
+			input:  DictionaryListRequest{Lang: "EN"},
+			wantItems: []ListItem{
+				{Code: "en", Language: "en", Labels: map[string]string{"env": "prod", "team": "search"}},
+				{Code: "en-us", Language: "en-US", Labels: map[string]string{"env": "dev"}},
+			},
+		},
+		{
+			name:   "filter by labels",
+			lister: filtered,
+			input:  DictionaryListRequest{Labels: []string{"team", "env=prod"}},
+			wantItems: []ListItem{
+				{Code: "en", Language: "en", Labels: map[string]string{"env": "prod", "team": "search"}},
+			},
+		},
+		{
+			name:      "nothing matches",
+			lister:    filtered,
+			input:     DictionaryListRequest{Lang: "en-GB"},
+			wantItems: []ListItem{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -60,7 +117,7 @@ func Test_DictionaryList(t *testing.T) {
 			interactor := dictionaryList(tt.lister)
 
 			var out DictionaryListResponse
-			err := interactor.Interact(context.Background(), Empty{}, &out)
+			err := interactor.Interact(context.Background(), tt.input, &out)
 
 			require.NoError(t, err)
 			require.Equal(t, tt.wantItems, out.Items)
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryRuleDeleteRequest struct {
+	Code string `path:"code" minLength:"1"`
+	ID   string `path:"id" minLength:"1" description:"Rule identifier"`
+}
+
+func dictionaryRuleDelete(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryRuleDeleteRequest, output *Empty) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		err = item.Rules.DeleteRule(input.ID)
+		if errors.Is(err, spellchecker.ErrRuleNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Delete a correction rule")
+	u.SetDescription("Removes a static correction rule from the dictionary")
+	u.SetExpectedErrors(status.Internal, status.NotFound)
+
+	return u
+}
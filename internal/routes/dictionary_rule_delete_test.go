@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryRuleDelete(t *testing.T) {
+	t.Parallel()
+
+	newRules := func(t *testing.T) *spellchecker.Rules {
+		r := spellchecker.NewRules()
+		require.NoError(t, r.SetRule(spellchecker.Rule{ID: "alot", Pattern: "alot", Replacement: "a lot"}))
+
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		input    DictionaryRuleDeleteRequest
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:   "success",
+			getter: &testDictionaryGetter{rules: newRules(t)},
+			input:  DictionaryRuleDeleteRequest{Code: "en", ID: "alot"},
+		},
+		{
+			name:     "rule not found",
+			getter:   &testDictionaryGetter{rules: newRules(t)},
+			input:    DictionaryRuleDeleteRequest{Code: "en", ID: "qwerty"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			input:    DictionaryRuleDeleteRequest{Code: "xx", ID: "alot"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			input:    DictionaryRuleDeleteRequest{Code: "en", ID: "alot"},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryRuleDelete(tt.getter).Interact(context.Background(), tt.input, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Empty(t, tt.getter.rules.ListRules())
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryRuleListRequest struct {
+	Code string `path:"code" minLength:"1"`
+}
+
+type DictionaryRuleListResponse struct {
+	Items []RuleItem `json:"items"`
+}
+
+type RuleItem struct {
+	ID          string `json:"id"`
+	Pattern     string `json:"pattern"`
+	Regexp      bool   `json:"regexp"`
+	Replacement string `json:"replacement"`
+}
+
+func dictionaryRuleList(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryRuleListRequest, output *DictionaryRuleListResponse) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		rules := item.Rules.ListRules()
+		result := make([]RuleItem, 0, len(rules))
+
+		for _, r := range rules {
+			result = append(result, RuleItem{
+				ID:          r.ID,
+				Pattern:     r.Pattern,
+				Regexp:      r.Regexp,
+				Replacement: r.Replacement,
+			})
+		}
+
+		output.Items = result
+
+		return nil
+	})
+
+	u.SetTitle("List correction rules")
+	u.SetDescription("Returns static correction rules of the dictionary")
+	u.SetExpectedErrors(status.Internal, status.NotFound)
+
+	return u
+}
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryRuleList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		rules := spellchecker.NewRules()
+		require.NoError(t, rules.SetRule(spellchecker.Rule{ID: "alot", Pattern: "alot", Replacement: "a lot"}))
+
+		var out DictionaryRuleListResponse
+		err := dictionaryRuleList(&testDictionaryGetter{rules: rules}).Interact(context.Background(), DictionaryRuleListRequest{Code: "en"}, &out)
+		require.NoError(t, err)
+		require.Equal(t, []RuleItem{{ID: "alot", Pattern: "alot", Replacement: "a lot"}}, out.Items)
+	})
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		wantCode status.Code
+	}{
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryRuleListResponse
+			err := dictionaryRuleList(tt.getter).Interact(context.Background(), DictionaryRuleListRequest{Code: "en"}, &out)
+			require.Error(t, err)
+			require.True(t, err.(isErr).Is(tt.wantCode))
+		})
+	}
+}
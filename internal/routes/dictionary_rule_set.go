@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type DictionaryRuleSetRequest struct {
+	Code string `path:"code" minLength:"1"`
+	ID   string `path:"id" minLength:"1" description:"Rule identifier"`
+
+	Pattern     string `json:"pattern" minLength:"1" description:"A word or a phrase to be replaced, or a regular expression if regexp is true. Matches must start and end at word boundaries."`
+	Regexp      bool   `json:"regexp" description:"The pattern is a regular expression."`
+	Replacement string `json:"replacement" description:"Replacement text. Regexp group references like $1 are expanded."`
+}
+
+func dictionaryRuleSet(registry dictionaryGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryRuleSetRequest, output *Empty) error {
+		item, err := registry.GetItem(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+		defer item.Release()
+
+		err = item.Rules.SetRule(spellchecker.Rule{
+			ID:          input.ID,
+			Pattern:     input.Pattern,
+			Regexp:      input.Regexp,
+			Replacement: input.Replacement,
+		})
+		if errors.Is(err, spellchecker.ErrInvalidRule) {
+			return status.Wrap(err, status.InvalidArgument)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Set a correction rule")
+	u.SetDescription("Creates or replaces a static correction rule of the dictionary. Rules are applied by fix requests before the spellchecker, e.g. to replace \"alot\" with \"a lot\".")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument)
+
+	return u
+}
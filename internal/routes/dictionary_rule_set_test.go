@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryRuleSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		getter   *testDictionaryGetter
+		input    DictionaryRuleSetRequest
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:   "success",
+			getter: &testDictionaryGetter{rules: spellchecker.NewRules()},
+			input:  DictionaryRuleSetRequest{Code: "en", ID: "alot", Pattern: "alot", Replacement: "a lot"},
+		},
+		{
+			name:     "invalid regexp",
+			getter:   &testDictionaryGetter{rules: spellchecker.NewRules()},
+			input:    DictionaryRuleSetRequest{Code: "en", ID: "re", Pattern: "(", Regexp: true},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   &testDictionaryGetter{err: spellchecker.ErrNotFound},
+			input:    DictionaryRuleSetRequest{Code: "xx", ID: "alot", Pattern: "alot"},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryGetter{err: errors.New("boom")},
+			input:    DictionaryRuleSetRequest{Code: "en", ID: "alot", Pattern: "alot"},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryRuleSet(tt.getter).Interact(context.Background(), tt.input, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Len(t, tt.getter.rules.ListRules(), 1)
+			}
+		})
+	}
+}
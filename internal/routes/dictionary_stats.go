@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type statsGetter interface {
+	Stats() spellchecker.LoadStats
+}
+
+type DictionaryStatsResponse struct {
+	Registered int   `json:"registered" description:"Number of all the dictionaries, loaded or not."`
+	Loaded     int   `json:"loaded" description:"Number of the dictionaries in memory."`
+	Size       int64 `json:"size" description:"Estimated size of the loaded dictionaries in bytes (by the size of their files)."`
+	Budget     int64 `json:"budget" description:"Memory budget in bytes, the least recently used dictionaries are unloaded when it is exceeded. 0 - unlimited."`
+
+	Loads      uint64  `json:"loads" description:"Number of dictionaries loaded since the start."`
+	LoadErrors uint64  `json:"loadErrors" description:"Number of failed loads since the start."`
+	Evictions  uint64  `json:"evictions" description:"Number of dictionaries unloaded to fit into the memory budget since the start."`
+	LoadTime   float64 `json:"loadTime" description:"Total time spent on loading dictionaries, in seconds."`
+}
+
+func dictionaryStats(registry statsGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *DictionaryStatsResponse) error {
+		stats := registry.Stats()
+
+		*output = DictionaryStatsResponse{
+			Registered: stats.Registered,
+			Loaded:     stats.Loaded,
+			Size:       stats.Size,
+			Budget:     stats.Budget,
+			Loads:      stats.Loads,
+			LoadErrors: stats.LoadErrors,
+			Evictions:  stats.Evictions,
+			LoadTime:   stats.LoadTime.Seconds(),
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Get dictionary loading stats")
+	u.SetDescription("Returns the number of loaded dictionaries, their estimated size and counters of loads and evictions.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatsGetter struct {
+	stats spellchecker.LoadStats
+}
+
+func (f *testStatsGetter) Stats() spellchecker.LoadStats {
+	return f.stats
+}
+
+func Test_DictionaryStats(t *testing.T) {
+	t.Parallel()
+
+	getter := &testStatsGetter{stats: spellchecker.LoadStats{
+		Registered: 3,
+		Loaded:     1,
+		Size:       1024,
+		Budget:     2048,
+		Loads:      5,
+		LoadErrors: 1,
+		Evictions:  4,
+		LoadTime:   1500 * time.Millisecond,
+	}}
+
+	var out DictionaryStatsResponse
+	err := dictionaryStats(getter).Interact(context.Background(), Empty{}, &out)
+	require.NoError(t, err)
+
+	require.Equal(t, DictionaryStatsResponse{
+		Registered: 3,
+		Loaded:     1,
+		Size:       1024,
+		Budget:     2048,
+		Loads:      5,
+		LoadErrors: 1,
+		Evictions:  4,
+		LoadTime:   1.5,
+	}, out)
+}
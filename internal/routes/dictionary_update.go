@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type dictionaryUpdater interface {
+	Update(code string, update spellchecker.InfoUpdate) (spellchecker.Options, error)
+}
+
+type DictionaryUpdateRequest struct {
+	Code string `path:"code" minLength:"1"`
+
+	Language    *string           `json:"language,omitempty" description:"BCP 47 language tag of the dictionary (e.g. en, de-CH)."`
+	Description *string           `json:"description,omitempty"`
+	Owner       *string           `json:"owner,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" description:"Replaces all the labels of the dictionary. Pass an empty object to remove them."`
+}
+
+func dictionaryUpdate(registry dictionaryUpdater) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryUpdateRequest, output *ListItem) error {
+		options, err := registry.Update(input.Code, spellchecker.InfoUpdate{
+			Language:    input.Language,
+			Description: input.Description,
+			Owner:       input.Owner,
+			Labels:      input.Labels,
+		})
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrInvalidOptions) {
+			return status.Wrap(err, status.InvalidArgument)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		*output = listItem(spellchecker.ListItem{Code: input.Code, Options: options})
+
+		return nil
+	})
+
+	u.SetTitle("Update a dictionary")
+	u.SetDescription("Changes the language, the description, the owner or the labels of a dictionary. Omitted fields are left as is.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument)
+
+	return u
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testDictionaryUpdater struct {
+	update spellchecker.InfoUpdate
+	err    error
+}
+
+func (f *testDictionaryUpdater) Update(code string, update spellchecker.InfoUpdate) (spellchecker.Options, error) {
+	f.update = update
+	if f.err != nil {
+		return spellchecker.Options{}, f.err
+	}
+
+	return spellchecker.Options{Language: *update.Language, Owner: "search-team"}, nil
+}
+
+func Test_DictionaryUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		updater  *testDictionaryUpdater
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:    "success",
+			updater: &testDictionaryUpdater{},
+		},
+		{
+			name:     "not found",
+			updater:  &testDictionaryUpdater{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "invalid options",
+			updater:  &testDictionaryUpdater{err: spellchecker.ErrInvalidOptions},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "internal error",
+			updater:  &testDictionaryUpdater{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := DictionaryUpdateRequest{
+				Code:     "en",
+				Language: ptr("en-US"),
+				Labels:   map[string]string{"env": "prod"},
+			}
+
+			var out ListItem
+			err := dictionaryUpdate(tt.updater).Interact(context.Background(), input, &out)
+
+			require.Equal(t, spellchecker.InfoUpdate{Language: ptr("en-US"), Labels: map[string]string{"env": "prod"}}, tt.updater.update)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, ListItem{Code: "en", Language: "en-US", Owner: "search-team"}, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type dictionaryVerifier interface {
+	Verify(code string) (spellchecker.FileInfo, error)
+}
+
+type DictionaryVerifyRequest struct {
+	Code string `path:"code" minLength:"1"`
+}
+
+type DictionaryVerifyResponse struct {
+	Valid       bool   `json:"valid" description:"The file is not corrupted and the dictionary can be loaded from it."`
+	Error       string `json:"error,omitempty" description:"Reason why the file is invalid."`
+	Version     uint16 `json:"version" description:"Format version of the file. 0 - the file was written by an older version of the service without the checksum, 1 - JSON, 2 - binary."`
+	Compression string `json:"compression,omitempty" description:"Compression of the file content."`
+	Size        int64  `json:"size" description:"Size of the file content in bytes."`
+	Checksum    string `json:"checksum,omitempty" description:"CRC-32C of the file content."`
+}
+
+func dictionaryVerify(registry dictionaryVerifier) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryVerifyRequest, output *DictionaryVerifyResponse) error {
+		info, err := registry.Verify(input.Code)
+		if errors.Is(err, spellchecker.ErrNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, os.ErrNotExist) {
+			return status.Wrap(fmt.Errorf("the dictionary is not saved yet"), status.FailedPrecondition)
+		} else if errors.Is(err, spellchecker.ErrCorrupted) || errors.Is(err, spellchecker.ErrUnsupportedFormat) {
+			output.Error = err.Error()
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Valid = err == nil
+		output.Version = info.Version
+		output.Compression = string(info.Compression)
+		output.Size = info.Size
+
+		if info.Size > 0 {
+			output.Checksum = fmt.Sprintf("%08x", info.Checksum)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Verify a dictionary file")
+	u.SetDescription("Checks the checksum of the dictionary file on disk and that the dictionary can be loaded from it. The dictionary in memory is not changed.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.FailedPrecondition)
+
+	return u
+}
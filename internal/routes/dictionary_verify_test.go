@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testDictionaryVerifier struct {
+	info spellchecker.FileInfo
+	err  error
+}
+
+func (f *testDictionaryVerifier) Verify(code string) (spellchecker.FileInfo, error) {
+	return f.info, f.err
+}
+
+func Test_DictionaryVerify(t *testing.T) {
+	t.Parallel()
+
+	info := spellchecker.FileInfo{Version: 1, Size: 100, Checksum: 0xabcdef}
+
+	tests := []struct {
+		name     string
+		verifier *testDictionaryVerifier
+		want     DictionaryVerifyResponse
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:     "valid",
+			verifier: &testDictionaryVerifier{info: info},
+			want:     DictionaryVerifyResponse{Valid: true, Version: 1, Size: 100, Checksum: "00abcdef"},
+		},
+		{
+			name:     "corrupted",
+			verifier: &testDictionaryVerifier{info: info, err: fmt.Errorf("%w: checksum mismatch", spellchecker.ErrCorrupted)},
+			want:     DictionaryVerifyResponse{Valid: false, Error: "file is corrupted: checksum mismatch", Version: 1, Size: 100, Checksum: "00abcdef"},
+		},
+		{
+			name:     "not saved",
+			verifier: &testDictionaryVerifier{err: os.ErrNotExist},
+			wantErr:  true,
+			wantCode: status.FailedPrecondition,
+		},
+		{
+			name:     "not found",
+			verifier: &testDictionaryVerifier{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			verifier: &testDictionaryVerifier{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryVerifyResponse
+			err := dictionaryVerify(tt.verifier).Interact(context.Background(), DictionaryVerifyRequest{Code: "en"}, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out)
+		})
+	}
+}
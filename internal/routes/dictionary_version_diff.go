@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type versionDiffer interface {
+	Diff(code string, from uint64, to uint64) (spellchecker.Diff, error)
+}
+
+type DictionaryVersionDiffRequest struct {
+	Code string `path:"code" minLength:"1"`
+
+	From uint64 `query:"from" required:"true" minimum:"1" description:"Version to compare."`
+	To   uint64 `query:"to" description:"Version to compare with. The current state of the dictionary is used by default."`
+}
+
+type DictionaryVersionDiffResponse struct {
+	Added      []WordChange `json:"added"`
+	Removed    []WordChange `json:"removed"`
+	Reweighted []WordChange `json:"reweighted"`
+}
+
+type WordChange struct {
+	Word string `json:"word"`
+	From uint   `json:"from" description:"Weight of the word in the first version."`
+	To   uint   `json:"to" description:"Weight of the word in the second version."`
+}
+
+func dictionaryVersionDiff(registry versionDiffer) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryVersionDiffRequest, output *DictionaryVersionDiffResponse) error {
+		d, err := registry.Diff(input.Code, input.From, input.To)
+		if errors.Is(err, spellchecker.ErrNotFound) || errors.Is(err, spellchecker.ErrVersionNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrWordsNotKept) {
+			return status.Wrap(err, status.FailedPrecondition)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Added = wordChanges(d.Added)
+		output.Removed = wordChanges(d.Removed)
+		output.Reweighted = wordChanges(d.Reweighted)
+
+		return nil
+	})
+
+	u.SetTitle("Compare dictionary versions")
+	u.SetDescription("Returns words added, removed and reweighted between two versions of the dictionary")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument, status.FailedPrecondition)
+
+	return u
+}
+
+func wordChanges(changes []spellchecker.WordChange) []WordChange {
+	result := make([]WordChange, 0, len(changes))
+	for _, c := range changes {
+		result = append(result, WordChange{Word: c.Word, From: c.From, To: c.To})
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryVersionDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		registry *testVersionRegistry
+		want     DictionaryVersionDiffResponse
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name: "success",
+			registry: &testVersionRegistry{diff: spellchecker.Diff{
+				Added:      []spellchecker.WordChange{{Word: "weapon", To: 1}},
+				Reweighted: []spellchecker.WordChange{{Word: "hello", From: 1, To: 3}},
+			}},
+			want: DictionaryVersionDiffResponse{
+				Added:      []WordChange{{Word: "weapon", To: 1}},
+				Removed:    []WordChange{},
+				Reweighted: []WordChange{{Word: "hello", From: 1, To: 3}},
+			},
+		},
+		{
+			name:     "dictionary not found",
+			registry: &testVersionRegistry{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "version not found",
+			registry: &testVersionRegistry{err: spellchecker.ErrVersionNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "words not kept",
+			registry: &testVersionRegistry{err: spellchecker.ErrWordsNotKept},
+			wantErr:  true,
+			wantCode: status.FailedPrecondition,
+		},
+		{
+			name:     "internal error",
+			registry: &testVersionRegistry{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryVersionDiffResponse
+			err := dictionaryVersionDiff(tt.registry).Interact(context.Background(), DictionaryVersionDiffRequest{Code: "en", From: 1}, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type versionLister interface {
+	ListVersions(code string) ([]spellchecker.Version, error)
+}
+
+type DictionaryVersionListRequest struct {
+	Code string `path:"code" minLength:"1"`
+}
+
+type DictionaryVersionListResponse struct {
+	Items []VersionItem `json:"items"`
+}
+
+type VersionItem struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size" description:"Size of the version file in bytes."`
+}
+
+func dictionaryVersionList(registry versionLister) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryVersionListRequest, output *DictionaryVersionListResponse) error {
+		versions, err := registry.ListVersions(input.Code)
+		if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Items = make([]VersionItem, 0, len(versions))
+		for _, v := range versions {
+			output.Items = append(output.Items, VersionItem{
+				ID:        v.ID,
+				CreatedAt: v.CreatedAt,
+				Size:      v.Size,
+			})
+		}
+
+		return nil
+	})
+
+	u.SetTitle("List dictionary versions")
+	u.SetDescription("Returns saved versions of the dictionary, the newest first. A version is created on each save request if versioning is enabled.")
+	u.SetExpectedErrors(status.Internal, status.NotFound)
+
+	return u
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testVersionRegistry struct {
+	versions []spellchecker.Version
+	diff     spellchecker.Diff
+	err      error
+
+	rolledBack uint64
+}
+
+func (f *testVersionRegistry) ListVersions(code string) ([]spellchecker.Version, error) {
+	return f.versions, f.err
+}
+
+func (f *testVersionRegistry) Diff(code string, from uint64, to uint64) (spellchecker.Diff, error) {
+	return f.diff, f.err
+}
+
+func (f *testVersionRegistry) Rollback(code string, id uint64) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.rolledBack = id
+
+	return nil
+}
+
+func Test_DictionaryVersionList(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		registry  *testVersionRegistry
+		wantItems []VersionItem
+		wantErr   bool
+		wantCode  status.Code
+	}{
+		{
+			name:      "empty",
+			registry:  &testVersionRegistry{},
+			wantItems: []VersionItem{},
+		},
+		{
+			name: "success",
+			registry: &testVersionRegistry{versions: []spellchecker.Version{
+				{ID: 2, CreatedAt: created, Size: 100},
+				{ID: 1, CreatedAt: created, Size: 50},
+			}},
+			wantItems: []VersionItem{
+				{ID: 2, CreatedAt: created, Size: 100},
+				{ID: 1, CreatedAt: created, Size: 50},
+			},
+		},
+		{
+			name:     "not found",
+			registry: &testVersionRegistry{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			registry: &testVersionRegistry{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out DictionaryVersionListResponse
+			err := dictionaryVersionList(tt.registry).Interact(context.Background(), DictionaryVersionListRequest{Code: "en"}, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantItems, out.Items)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type versionRollbacker interface {
+	Rollback(code string, id uint64) error
+}
+
+type DictionaryVersionRollbackRequest struct {
+	Code    string `path:"code" minLength:"1"`
+	Version uint64 `path:"version" minimum:"1"`
+}
+
+func dictionaryVersionRollback(registry versionRollbacker) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input DictionaryVersionRollbackRequest, output *Empty) error {
+		err := registry.Rollback(input.Code, input.Version)
+		if errors.Is(err, spellchecker.ErrNotFound) || errors.Is(err, spellchecker.ErrVersionNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if errors.Is(err, spellchecker.ErrNoVersions) {
+			return status.Wrap(err, status.FailedPrecondition)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Roll back a dictionary")
+	u.SetDescription("Replaces the dictionary with the saved version. The current state is saved as a new version before that, so versioning must be enabled.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument, status.FailedPrecondition)
+
+	return u
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+func Test_DictionaryVersionRollback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		registry *testVersionRegistry
+		wantErr  bool
+		wantCode status.Code
+	}{
+		{
+			name:     "success",
+			registry: &testVersionRegistry{},
+		},
+		{
+			name:     "dictionary not found",
+			registry: &testVersionRegistry{err: spellchecker.ErrNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "version not found",
+			registry: &testVersionRegistry{err: spellchecker.ErrVersionNotFound},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "versioning disabled",
+			registry: &testVersionRegistry{err: spellchecker.ErrNoVersions},
+			wantErr:  true,
+			wantCode: status.FailedPrecondition,
+		},
+		{
+			name:     "internal error",
+			registry: &testVersionRegistry{err: errors.New("boom")},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out Empty
+			err := dictionaryVersionRollback(tt.registry).Interact(context.Background(), DictionaryVersionRollbackRequest{Code: "en", Version: 3}, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, uint64(3), tt.registry.rolledBack)
+			}
+		})
+	}
+}
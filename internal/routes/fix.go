@@ -0,0 +1,240 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/f1monkey/spellchecker-web/internal/langdetect"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/tokenizer"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type dictionaryDetector interface {
+	dictionaryGetter
+	dictionaryLister
+	Profile(name string, key string) (string, *langdetect.Profile, error)
+}
+
+type FixRequest struct {
+	RoutingKey string `header:"X-Routing-Key" description:"Requests with the same key are always routed to the same dictionary of an alias with several targets (e.g. a user id). A random dictionary is chosen by weights if it is empty."`
+
+	Text  string `json:"text" description:"Text to be checked, may contain sentences in different languages"`
+	Limit int    `json:"limit" default:"5" desciption:"Max suggestions per word"`
+
+	Dictionaries []string `json:"dictionaries,omitempty" description:"Codes or aliases of the dictionaries to choose from. All the dictionaries are used by default."`
+	Languages    []string `json:"languages,omitempty" description:"Only dictionaries with one of the declared languages (or their subtags, en matches en-US) are used."`
+
+	MinScore  *float64 `json:"minScore,omitempty" minimum:"0" description:"Suggestions with a lower score are dropped. Overrides the dictionary settings."`
+	MinMargin *float64 `json:"minMargin,omitempty" minimum:"0" description:"Min gap between the scores of the first and the second suggestion required for medium or high confidence. Overrides the dictionary settings."`
+}
+
+type FixResponse struct {
+	Fixes   []Fix     `json:"fixes" description:"List of detected issues."`
+	Correct []Correct `json:"correct" description:"List of correct words."`
+	Spans   []Span    `json:"spans" description:"Sentences of the text with the dictionaries used to check them."`
+}
+
+type Span struct {
+	Start      int    `json:"start" description:"Starting character index of the sentence in the input."`
+	End        int    `json:"end" description:"Ending character index."`
+	Dictionary string `json:"dictionary" description:"Dictionary used to check the sentence."`
+	Language   string `json:"language,omitempty" description:"Declared language of the dictionary."`
+}
+
+type detectCandidate struct {
+	code     string
+	language string
+	profile  *langdetect.Profile
+}
+
+// fix checks the text, unknown words are collected as candidates if collect is true.
+// In cluster mode remote returns the peer owning the name if it is not the local one, the text is checked with the local dictionaries only.
+func fix(registry dictionaryDetector, splitter *regexp.Regexp, collect bool, remote func(name string) string) usecase.Interactor {
+	tokens := tokenizer.New(splitter)
+
+	u := usecase.NewInteractor(func(ctx context.Context, input FixRequest, output *FixResponse) error {
+		if remote != nil {
+			for _, name := range input.Dictionaries {
+				if owner := remote(name); owner != "" {
+					return status.Wrap(fmt.Errorf("%q is owned by the peer %s, /fix uses the local dictionaries only", name, owner), status.InvalidArgument)
+				}
+			}
+		}
+
+		candidates, err := detectCandidates(registry, input.Dictionaries, input.Languages, input.RoutingKey)
+		if errors.Is(spellchecker.ErrNotFound, err) && remote != nil && len(input.Dictionaries) == 0 {
+			return status.Wrap(fmt.Errorf("%w: no local dictionaries match the languages, /fix uses the local dictionaries only", err), status.NotFound)
+		} else if errors.Is(spellchecker.ErrNotFound, err) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		// only the dictionaries chosen for some sentence are loaded
+		items := make(map[string]spellchecker.RegistryItem)
+		defer func() {
+			for _, item := range items {
+				item.Release()
+			}
+		}()
+
+		output.Fixes = make([]Fix, 0)
+		output.Correct = make([]Correct, 0)
+		output.Spans = make([]Span, 0)
+
+		for _, s := range langdetect.Spans(input.Text) {
+			c, ok := detect(candidates, input.Text[s.Start:s.End])
+			if !ok {
+				continue
+			}
+
+			item, ok := items[c.code]
+			if !ok {
+				item, err = registry.GetItem(c.code)
+				if errors.Is(spellchecker.ErrNotFound, err) {
+					return status.Wrap(err, status.NotFound)
+				} else if err != nil {
+					return status.Wrap(err, status.Internal)
+				}
+
+				items[c.code] = item
+			}
+
+			f := &fixer{
+				item:      item,
+				layoutSc:  item.Spellchecker,
+				collect:   collect,
+				limit:     input.Limit,
+				minScore:  item.Options.MinScore,
+				minMargin: item.Options.MinMargin,
+			}
+
+			if input.MinScore != nil {
+				f.minScore = *input.MinScore
+			}
+
+			if input.MinMargin != nil {
+				f.minMargin = *input.MinMargin
+			}
+
+			text := input.Text[s.Start:s.End]
+			offset := utf8.RuneCountInString(input.Text[:s.Start])
+
+			fixes, correct := f.fix(text, tokens.Tokens(text))
+			for _, item := range fixes {
+				item.Start += offset
+				item.End += offset
+				item.Dictionary = c.code
+				output.Fixes = append(output.Fixes, item)
+			}
+
+			for _, item := range correct {
+				item.Start += offset
+				item.End += offset
+				output.Correct = append(output.Correct, item)
+			}
+
+			output.Spans = append(output.Spans, Span{
+				Start:      offset,
+				End:        offset + utf8.RuneCountInString(text),
+				Dictionary: c.code,
+				Language:   c.language,
+			})
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Fix text in any language")
+	u.SetDescription("Splits the text into sentences, detects the language of each one and checks it with the best matching dictionary. The language is detected by character n-grams of the dictionaries words. In cluster mode only the dictionaries of the peer are used, names owned by other peers are rejected.")
+	u.SetExpectedErrors(status.Internal, status.NotFound, status.InvalidArgument)
+
+	return u
+}
+
+// detectCandidates returns the profiles of the dictionaries to choose from sorted by code. Aliases are resolved to the concrete dictionaries.
+func detectCandidates(registry dictionaryDetector, names []string, languages []string, key string) ([]detectCandidate, error) {
+	list := registry.List()
+
+	options := make(map[string]spellchecker.Options, len(list))
+	for _, item := range list {
+		options[item.Code] = item.Options
+	}
+
+	if len(names) == 0 {
+		// the dictionaries are filtered by the language before their profiles are built
+		for _, item := range list {
+			if hasLanguage(item.Options.Language, languages) {
+				names = append(names, item.Code)
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(names))
+	result := make([]detectCandidate, 0, len(names))
+
+	for _, name := range names {
+		code, profile, err := registry.Profile(name, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		language := options[code].Language
+		if !hasLanguage(language, languages) {
+			continue
+		}
+
+		result = append(result, detectCandidate{code: code, language: language, profile: profile})
+	}
+
+	if len(result) == 0 {
+		return nil, spellchecker.ErrNotFound
+	}
+
+	slices.SortFunc(result, func(a, b detectCandidate) int { return strings.Compare(a.code, b.code) })
+
+	return result, nil
+}
+
+// hasLanguage checks if the tag matches one of the languages, any tag matches an empty list
+func hasLanguage(tag string, languages []string) bool {
+	return len(languages) == 0 || slices.ContainsFunc(languages, func(l string) bool {
+		return matchLanguage(tag, l)
+	})
+}
+
+// detect returns the dictionary which profile matches the text best
+func detect(candidates []detectCandidate, text string) (detectCandidate, bool) {
+	grams := langdetect.Grams(text)
+	if len(grams) == 0 {
+		return detectCandidate{}, false
+	}
+
+	var result detectCandidate
+	best := math.Inf(-1)
+
+	for _, c := range candidates {
+		if c.profile.Len() == 0 {
+			continue
+		}
+
+		if score := c.profile.Score(grams); score > best {
+			result, best = c, score
+		}
+	}
+
+	return result, !math.IsInf(best, -1)
+}
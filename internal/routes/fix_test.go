@@ -0,0 +1,214 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/langdetect"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testDictionaryDetector struct {
+	items   map[string]spellchecker.RegistryItem
+	aliases map[string]string
+	err     error
+}
+
+func (f *testDictionaryDetector) GetItem(code string) (spellchecker.RegistryItem, error) {
+	if f.err != nil {
+		return spellchecker.RegistryItem{}, f.err
+	}
+
+	item, ok := f.items[code]
+	if !ok {
+		return spellchecker.RegistryItem{}, spellchecker.ErrNotFound
+	}
+
+	return item, nil
+}
+
+func (f *testDictionaryDetector) Profile(name string, key string) (string, *langdetect.Profile, error) {
+	if code, ok := f.aliases[name]; ok {
+		name = code
+	}
+
+	item, err := f.GetItem(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, item.Profile(), nil
+}
+
+func (f *testDictionaryDetector) List() []spellchecker.ListItem {
+	result := make([]spellchecker.ListItem, 0, len(f.items))
+	for code, item := range f.items {
+		result = append(result, spellchecker.ListItem{Code: code, Options: item.Options})
+	}
+
+	return result
+}
+
+func Test_Fix(t *testing.T) {
+	t.Parallel()
+
+	splitter := regexp.MustCompile(`[\p{L}]+`)
+
+	en, err := f1mspellchecker.New(f1mspellchecker.DefaultAlphabet)
+	require.NoError(t, err)
+
+	enItem := spellchecker.RegistryItem{Spellchecker: en, Words: spellchecker.NewWords(), Options: spellchecker.Options{Language: "en"}}
+	enItem.AddWeight(1, "hello", "world", "this", "is", "the", "quick", "brown", "fox")
+
+	ru, err := f1mspellchecker.New("абвгдеёжзийклмнопрстуфхцчшщъыьэюя")
+	require.NoError(t, err)
+
+	ruItem := spellchecker.RegistryItem{Spellchecker: ru, Words: spellchecker.NewWords(), Options: spellchecker.Options{Language: "ru"}}
+	ruItem.AddWeight(1, "привет", "мир", "это", "быстрая", "лиса")
+
+	detector := &testDictionaryDetector{
+		items: map[string]spellchecker.RegistryItem{
+			"en": enItem,
+			"ru": ruItem,
+		},
+		aliases: map[string]string{"russian": "ru"},
+	}
+
+	tests := []struct {
+		name      string
+		getter    *testDictionaryDetector
+		remote    func(name string) string
+		input     FixRequest
+		wantErr   bool
+		wantCode  status.Code
+		wantFixes []Fix
+		wantSpans []Span
+	}{
+		{
+			name:   "mixed languages",
+			getter: detector,
+			input:  FixRequest{Text: "hello wrld. привет, мирр!", Limit: 1},
+			wantFixes: []Fix{
+				{
+					Start: 6, End: 10, Error: "invalid_word", Confidence: "high", Dictionary: "en",
+					Suggestions: []SpellcheckerSuggestion{{Text: "world", Source: "edit_distance"}},
+				},
+				{
+					Start: 20, End: 24, Error: "invalid_word", Confidence: "high", Dictionary: "ru",
+					Suggestions: []SpellcheckerSuggestion{{Text: "мир", Source: "edit_distance"}},
+				},
+			},
+			wantSpans: []Span{
+				{Start: 0, End: 11, Dictionary: "en", Language: "en"},
+				{Start: 12, End: 25, Dictionary: "ru", Language: "ru"},
+			},
+		},
+		{
+			name:      "filtered by language",
+			getter:    detector,
+			input:     FixRequest{Text: "Привет мир", Languages: []string{"EN"}},
+			wantFixes: nil,
+			wantSpans: []Span{{Start: 0, End: 10, Dictionary: "en", Language: "en"}},
+		},
+		{
+			name:      "selected dictionaries",
+			getter:    detector,
+			input:     FixRequest{Text: "hello", Dictionaries: []string{"ru"}},
+			wantFixes: nil,
+			wantSpans: []Span{{Start: 0, End: 5, Dictionary: "ru", Language: "ru"}},
+		},
+		{
+			name:      "selected alias",
+			getter:    detector,
+			input:     FixRequest{Text: "hello", Dictionaries: []string{"russian"}},
+			wantFixes: nil,
+			wantSpans: []Span{{Start: 0, End: 5, Dictionary: "ru", Language: "ru"}},
+		},
+		{
+			name:      "no words",
+			getter:    detector,
+			input:     FixRequest{Text: "123. 456"},
+			wantSpans: []Span{},
+		},
+		{
+			name:     "no dictionaries with the language",
+			getter:   detector,
+			input:    FixRequest{Text: "hello", Languages: []string{"de"}},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "dictionary not found",
+			getter:   detector,
+			input:    FixRequest{Text: "hello", Dictionaries: []string{"de"}},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "remote dictionary",
+			getter:   detector,
+			remote:   func(name string) string { return map[string]string{"de": "http://peer2:8080"}[name] },
+			input:    FixRequest{Text: "hello", Dictionaries: []string{"en", "de"}},
+			wantErr:  true,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:      "local dictionary in cluster mode",
+			getter:    detector,
+			remote:    func(name string) string { return map[string]string{"de": "http://peer2:8080"}[name] },
+			input:     FixRequest{Text: "hello", Dictionaries: []string{"ru"}},
+			wantFixes: nil,
+			wantSpans: []Span{{Start: 0, End: 5, Dictionary: "ru", Language: "ru"}},
+		},
+		{
+			name:     "no local dictionaries with the language in cluster mode",
+			getter:   detector,
+			remote:   func(name string) string { return "" },
+			input:    FixRequest{Text: "hello", Languages: []string{"de"}},
+			wantErr:  true,
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			getter:   &testDictionaryDetector{err: errors.New("boom")},
+			input:    FixRequest{Text: "hello", Dictionaries: []string{"en"}},
+			wantErr:  true,
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out FixResponse
+			err := fix(tt.getter, splitter, true, tt.remote).Interact(context.Background(), tt.input, &out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSpans, out.Spans)
+
+			if tt.wantFixes == nil {
+				return
+			}
+
+			require.Len(t, out.Fixes, len(tt.wantFixes))
+			for i := range tt.wantFixes {
+				for j := range out.Fixes[i].Suggestions {
+					out.Fixes[i].Suggestions[j].Score = 0
+				}
+			}
+			require.Equal(t, tt.wantFixes, out.Fixes)
+		})
+	}
+}
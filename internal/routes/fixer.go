@@ -0,0 +1,361 @@
+package routes
+
+import (
+	"slices"
+	"sort"
+	"unicode/utf8"
+
+	f1mspellchecker "github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/compound"
+	"github.com/f1monkey/spellchecker-web/internal/layout"
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/tokenizer"
+)
+
+const (
+	errorUnknownWord   = "unknown_word"
+	errorInvalidWord   = "invalid_word"
+	errorWrongLayout   = "wrong_layout"
+	errorReplacement   = "replacement"
+	errorForbiddenWord = "forbidden_word"
+
+	sourceEditDistance = "edit_distance"
+	sourcePhonetic     = "phonetic"
+	sourceLayout       = "layout"
+	sourceFeedback     = "feedback"
+	sourceRule         = "rule"
+
+	confidenceHigh   = "high"
+	confidenceMedium = "medium"
+	confidenceLow    = "low"
+)
+
+// fixer checks words of a text against a single dictionary
+type fixer struct {
+	item     spellchecker.RegistryItem
+	layouts  []layout.Pair
+	layoutSc *f1mspellchecker.Spellchecker
+	collect  bool // unknown words are collected as candidates
+
+	limit     int
+	minScore  float64
+	minMargin float64
+}
+
+func (f *fixer) fix(text string, tokens []tokenizer.Token) ([]Fix, []Correct) {
+	fixes := make([]Fix, 0, len(tokens))
+	correct := make([]Correct, 0, len(tokens))
+
+	// static rules are applied first, words matched by them are not checked by the spellchecker
+	matches := f.item.Rules.Match(text)
+	for _, m := range matches {
+		fixes = append(fixes, Fix{
+			Start:       utf8.RuneCountInString(text[:m.Start]),
+			End:         utf8.RuneCountInString(text[:m.End]),
+			Error:       errorReplacement,
+			Confidence:  confidenceHigh,
+			Suggestions: []SpellcheckerSuggestion{{Text: m.Replacement, Score: 1, Source: sourceRule}},
+		})
+	}
+
+	for _, token := range tokens {
+		words := []tokenizer.Token{token}
+
+		if len(token.Parts) > 0 && !overlaps(matches, token.Start, token.End) {
+			// the dictionary knows the whole hyphenated word, there is no need to check its parts
+			if f.item.Spellchecker.IsCorrect(token.Text) {
+				words = words[:0]
+				correct = append(correct, Correct{
+					Start: utf8.RuneCountInString(text[:token.Start]),
+					End:   utf8.RuneCountInString(text[:token.End]),
+				})
+			} else {
+				words = token.Parts
+			}
+		}
+
+		for _, w := range words {
+			if overlaps(matches, w.Start, w.End) {
+				continue
+			}
+
+			startRune := utf8.RuneCountInString(text[:w.Start])
+			endRune := startRune + utf8.RuneCountInString(w.Text)
+
+			fix, ok := f.checkWord(w.Text, startRune, endRune)
+			if ok {
+				correct = append(correct, Correct{
+					Start: startRune,
+					End:   endRune,
+				})
+
+				continue
+			}
+
+			if f.collect && isCandidate(fix) {
+				f.item.Collect(w.Text, sample(text, w.Start, w.End))
+			}
+
+			fixes = append(fixes, fix)
+		}
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool { return fixes[i].Start < fixes[j].Start })
+
+	return fixes, correct
+}
+
+// layoutTokens replaces the tokens with the ones including the punctuation keys mapped to letters on another layout
+// (e.g. "vj" and "yj" with "vj;yj"), if the latter are converted to known words
+func (f *fixer) layoutTokens(tokens []tokenizer.Token, extended []tokenizer.Token) []tokenizer.Token {
+	result := make([]tokenizer.Token, 0, len(tokens))
+
+	i := 0
+	for _, e := range extended {
+		for i < len(tokens) && tokens[i].End <= e.Start {
+			result = append(result, tokens[i])
+			i++
+		}
+
+		j := i
+		for j < len(tokens) && tokens[j].Start < e.End {
+			j++
+		}
+
+		if _, ok := convertLayout(f.layoutSc, f.layouts, e.Text); ok && (j-i != 1 || tokens[i].Text != e.Text) {
+			result = append(result, e)
+		} else {
+			result = append(result, tokens[i:j]...)
+		}
+
+		i = j
+	}
+
+	return append(result, tokens[i:]...)
+}
+
+func overlaps(matches []spellchecker.RuleMatch, start int, end int) bool {
+	for _, m := range matches {
+		if m.Start < end && start < m.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCandidate checks if the word of the fix is likely to be a new term (a product name, slang etc.)
+func isCandidate(fix Fix) bool {
+	if fix.Error != errorUnknownWord && fix.Error != errorInvalidWord {
+		return false
+	}
+
+	return len(fix.Suggestions) == 0 || fix.Suggestions[0].Source != sourceFeedback
+}
+
+// sample returns a part of the text around the word
+func sample(text string, start int, end int) string {
+	const width = 30
+
+	from := start
+	for i := 0; i < width && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+
+	to := end
+	for i := 0; i < width && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	return text[from:to]
+}
+
+// checkWord returns true if the word is correct, otherwise returns a fix for it
+func (f *fixer) checkWord(word string, startRune int, endRune int) (Fix, bool) {
+	fix := Fix{
+		Start: startRune,
+		End:   endRune,
+	}
+
+	if f.item.Rules.IsForbidden(word) {
+		fix.Error = errorForbiddenWord
+		fix.Confidence = confidenceHigh
+
+		return fix, false
+	}
+
+	suggestions := f.item.Spellchecker.SuggestScore(word, f.candidates())
+	if suggestions.ExactMatch {
+		return fix, true
+	}
+
+	if correction, ok := f.item.Feedback.Correction(word); ok {
+		fix.Error = errorInvalidWord
+		fix.Confidence = confidenceHigh
+		fix.Suggestions = []SpellcheckerSuggestion{{Text: correction, Score: 1, Source: sourceFeedback}}
+
+		return fix, false
+	}
+
+	if converted, ok := convertLayout(f.layoutSc, f.layouts, word); ok {
+		fix.Error = errorWrongLayout
+		fix.Confidence = confidenceHigh
+		fix.Suggestions = []SpellcheckerSuggestion{{Text: converted, Score: 1, Source: sourceLayout}}
+
+		return fix, false
+	}
+
+	if f.item.Options.Compound != nil {
+		if _, ok := compound.Split(word, f.item.Spellchecker.IsCorrect, *f.item.Options.Compound); ok {
+			return fix, true
+		}
+	}
+
+	fix.Suggestions = f.suggestions(word, suggestions.Suggestions)
+
+	if len(fix.Suggestions) == 0 && f.item.Options.Compound != nil {
+		fix = f.fixCompoundPart(word, fix)
+	}
+
+	if len(fix.Suggestions) == 0 {
+		fix.Error = errorUnknownWord
+		fix.Confidence = confidenceLow
+		fix.Suggestions = nil
+	} else {
+		fix.Error = errorInvalidWord
+		fix.Confidence = confidence(fix.Suggestions, f.minMargin)
+
+		// more candidates than requested may be found to calculate the margin
+		if len(fix.Suggestions) > f.limit {
+			fix.Suggestions = fix.Suggestions[:f.limit]
+		}
+	}
+
+	return fix, false
+}
+
+// candidates returns the number of suggestions requested from the spellchecker.
+// At least two suggestions are required to calculate the margin between them, the extra ones are not returned.
+func (f *fixer) candidates() int {
+	return max(f.limit, 2)
+}
+
+// suggestions combines spellchecker suggestions with the ones found by the phonetic index (if any)
+// and drops the ones with a score less than the min score
+func (f *fixer) suggestions(word string, matches []f1mspellchecker.Match) []SpellcheckerSuggestion {
+	result := make([]SpellcheckerSuggestion, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+
+	for _, m := range matches {
+		result = append(result, SpellcheckerSuggestion{
+			Text:   m.Value,
+			Score:  m.Score,
+			Source: sourceEditDistance,
+		})
+		seen[m.Value] = struct{}{}
+	}
+
+	if f.item.Phonetic != nil {
+		for _, m := range f.item.Phonetic.Find(word, f.candidates()) {
+			if _, ok := seen[m.Value]; ok {
+				continue
+			}
+
+			result = append(result, SpellcheckerSuggestion{
+				Text:   m.Value,
+				Score:  m.Score,
+				Source: sourcePhonetic,
+			})
+		}
+	}
+
+	result = slices.DeleteFunc(result, func(s SpellcheckerSuggestion) bool {
+		return s.Score < f.minScore || f.item.Feedback.IsRejected(word, s.Text)
+	})
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+
+	if len(result) > f.candidates() {
+		result = result[:f.candidates()]
+	}
+
+	return result
+}
+
+// fixCompoundPart looks for a misspelled part inside a compound word.
+// If the part can be corrected, the fix is narrowed to the part offsets.
+func (f *fixer) fixCompoundPart(word string, fix Fix) Fix {
+	parts, idx, ok := compound.SplitUnknown(word, f.item.Spellchecker.IsCorrect, *f.item.Options.Compound)
+	if !ok {
+		return fix
+	}
+
+	part := parts[idx]
+
+	suggestions := f.item.Spellchecker.SuggestScore(part.Text, f.candidates())
+	if len(suggestions.Suggestions) == 0 {
+		return fix
+	}
+
+	result := Fix{
+		Start:       fix.Start + part.Start,
+		End:         fix.Start + part.End,
+		Suggestions: f.suggestions(part.Text, suggestions.Suggestions),
+	}
+
+	if len(result.Suggestions) == 0 {
+		return fix
+	}
+
+	for i := range result.Suggestions {
+		result.Suggestions[i].Text = compound.MatchCase(result.Suggestions[i].Text, part.Text)
+	}
+
+	return result
+}
+
+// confidence classifies suggestions by the gap between the first and the second one:
+// low if the gap is less than the min margin, otherwise depends on the gap relative to the best score.
+func confidence(suggestions []SpellcheckerSuggestion, minMargin float64) string {
+	top := suggestions[0].Score
+	if top <= 0 {
+		return confidenceLow
+	}
+
+	margin := top
+	if len(suggestions) > 1 {
+		margin -= suggestions[1].Score
+	}
+
+	if margin < minMargin {
+		return confidenceLow
+	}
+
+	switch ratio := margin / top; {
+	case ratio >= 0.5:
+		return confidenceHigh
+	case ratio >= 0.2:
+		return confidenceMedium
+	default:
+		return confidenceLow
+	}
+}
+
+// convertLayout returns the word converted to another keyboard layout
+// if the converted word is known by the spellchecker.
+func convertLayout(sc *f1mspellchecker.Spellchecker, layouts []layout.Pair, word string) (string, bool) {
+	for _, p := range layouts {
+		converted, ok := p.Convert(word)
+		if !ok {
+			continue
+		}
+
+		if sc.IsCorrect(converted) {
+			return converted, true
+		}
+	}
+
+	return "", false
+}
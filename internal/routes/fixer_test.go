@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_confidence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		suggestions []SpellcheckerSuggestion
+		minMargin   float64
+		want        string
+	}{
+		{
+			name:        "single suggestion",
+			suggestions: []SpellcheckerSuggestion{{Score: 0.5}},
+			want:        confidenceHigh,
+		},
+		{
+			name:        "single suggestion below min margin",
+			suggestions: []SpellcheckerSuggestion{{Score: 0.5}},
+			minMargin:   1,
+			want:        confidenceLow,
+		},
+		{
+			name:        "zero score",
+			suggestions: []SpellcheckerSuggestion{{Score: 0}},
+			want:        confidenceLow,
+		},
+		{
+			name:        "large gap",
+			suggestions: []SpellcheckerSuggestion{{Score: 1}, {Score: 0.4}},
+			want:        confidenceHigh,
+		},
+		{
+			name:        "medium gap",
+			suggestions: []SpellcheckerSuggestion{{Score: 1}, {Score: 0.7}},
+			want:        confidenceMedium,
+		},
+		{
+			name:        "small gap",
+			suggestions: []SpellcheckerSuggestion{{Score: 1}, {Score: 0.9}},
+			want:        confidenceLow,
+		},
+		{
+			name:        "gap below min margin",
+			suggestions: []SpellcheckerSuggestion{{Score: 1}, {Score: 0.4}},
+			minMargin:   0.7,
+			want:        confidenceLow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, confidence(tt.suggestions, tt.minMargin))
+		})
+	}
+}
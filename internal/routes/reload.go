@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type reloader interface {
+	Reload(ctx context.Context) (spellchecker.ReloadResult, error)
+}
+
+type ReloadResponse struct {
+	Added    []string `json:"added" description:"Dictionaries loaded from the new files."`
+	Replaced []string `json:"replaced" description:"Dictionaries replaced with the changed files."`
+	Removed  []string `json:"removed" description:"Dictionaries unloaded as their files are removed."`
+	Metadata bool     `json:"metadata" description:"The aliases are re-read from the changed metadata file."`
+	Errors   []string `json:"errors,omitempty" description:"Files failed to load, the dictionaries in memory are kept."`
+}
+
+func reload(registry reloader) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *ReloadResponse) error {
+		result, err := registry.Reload(ctx)
+		if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Added = result.Added
+		output.Replaced = result.Replaced
+		output.Removed = result.Removed
+		output.Metadata = result.Metadata
+		output.Errors = result.Errors
+
+		if output.Added == nil {
+			output.Added = []string{}
+		}
+
+		if output.Replaced == nil {
+			output.Replaced = []string{}
+		}
+
+		if output.Removed == nil {
+			output.Removed = []string{}
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Reload the dictionaries")
+	u.SetDescription("Applies the changes of the dictionary files and the metadata made outside of the service: loads new files, swaps in replaced ones and unloads the dictionaries which files are removed.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
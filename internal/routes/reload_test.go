@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testReloader struct {
+	result spellchecker.ReloadResult
+	err    error
+}
+
+func (f *testReloader) Reload(ctx context.Context) (spellchecker.ReloadResult, error) {
+	return f.result, f.err
+}
+
+func Test_Reload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		registry *testReloader
+		want     ReloadResponse
+		wantCode status.Code
+	}{
+		{
+			name:     "nothing changed",
+			registry: &testReloader{},
+			want:     ReloadResponse{Added: []string{}, Replaced: []string{}, Removed: []string{}},
+		},
+		{
+			name: "changed",
+			registry: &testReloader{result: spellchecker.ReloadResult{
+				Added:    []string{"de"},
+				Replaced: []string{"en"},
+				Removed:  []string{"fr"},
+				Metadata: true,
+				Errors:   []string{`dictionary "es": unexpected EOF`},
+			}},
+			want: ReloadResponse{
+				Added:    []string{"de"},
+				Replaced: []string{"en"},
+				Removed:  []string{"fr"},
+				Metadata: true,
+				Errors:   []string{`dictionary "es": unexpected EOF`},
+			},
+		},
+		{
+			name:     "internal error",
+			registry: &testReloader{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out ReloadResponse
+			err := reload(tt.registry).Interact(context.Background(), Empty{}, &out)
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out)
+		})
+	}
+}
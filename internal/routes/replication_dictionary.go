@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type dictionaryWriter interface {
+	WriteDictionary(code string, w io.Writer) error
+}
+
+type ReplicationDictionaryRequest struct {
+	Code string `path:"code" minLength:"1"`
+}
+
+type ReplicationDictionaryResponse struct {
+	usecase.OutputWithEmbeddedWriter
+
+	ContentType string `header:"Content-Type"`
+}
+
+func replicationDictionary(registry dictionaryWriter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input ReplicationDictionaryRequest, output *ReplicationDictionaryResponse) error {
+		output.ContentType = "application/octet-stream"
+
+		err := registry.WriteDictionary(input.Code, output)
+		if errors.Is(err, spellchecker.ErrNotFound) {
+			return status.Wrap(err, status.NotFound)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Download the dictionary file")
+	u.SetDescription("Streams the file of the dictionary with its current state (aliases are not resolved). Used by replication followers.")
+	u.SetExpectedErrors(status.NotFound, status.Internal)
+
+	return u
+}
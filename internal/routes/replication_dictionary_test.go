@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testDictionaryWriter struct {
+	err error
+}
+
+func (f *testDictionaryWriter) WriteDictionary(code string, w io.Writer) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	_, err := w.Write([]byte("file of " + code))
+
+	return err
+}
+
+func Test_ReplicationDictionary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		registry *testDictionaryWriter
+		wantCode status.Code
+	}{
+		{
+			name:     "success",
+			registry: &testDictionaryWriter{},
+		},
+		{
+			name:     "not found",
+			registry: &testDictionaryWriter{err: spellchecker.ErrNotFound},
+			wantCode: status.NotFound,
+		},
+		{
+			name:     "internal error",
+			registry: &testDictionaryWriter{err: errors.New("boom")},
+			wantCode: status.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			out := ReplicationDictionaryResponse{}
+			out.SetWriter(&buf)
+
+			err := replicationDictionary(tt.registry).Interact(context.Background(), ReplicationDictionaryRequest{Code: "en"}, &out)
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "application/octet-stream", out.ContentType)
+			require.Equal(t, "file of en", buf.String())
+		})
+	}
+}
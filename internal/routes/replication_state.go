@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type replicationStateGetter interface {
+	ReplicationState() (spellchecker.ReplicationState, error)
+}
+
+type ReplicationStateResponse struct {
+	Dictionaries map[string]string     `json:"dictionaries" description:"Versions of the dictionaries by code. A version changes on each change of the dictionary."`
+	Metadata     spellchecker.Metadata `json:"metadata" description:"Aliases and their history."`
+}
+
+func replicationState(registry replicationStateGetter) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *ReplicationStateResponse) error {
+		state, err := registry.ReplicationState()
+		if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Dictionaries = state.Dictionaries
+		output.Metadata = state.Metadata
+
+		return nil
+	})
+
+	u.SetTitle("Get the replication state")
+	u.SetDescription("Returns the versions of the dictionaries and the aliases. Followers poll it and download the dictionaries which versions have changed.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
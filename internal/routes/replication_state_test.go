@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testReplicationStateGetter struct {
+	state spellchecker.ReplicationState
+	err   error
+}
+
+func (f *testReplicationStateGetter) ReplicationState() (spellchecker.ReplicationState, error) {
+	return f.state, f.err
+}
+
+func Test_ReplicationState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		getter := &testReplicationStateGetter{state: spellchecker.ReplicationState{
+			Dictionaries: map[string]string{"en": "v1"},
+			Metadata:     spellchecker.Metadata{Aliases: map[string]string{"main": "en"}},
+		}}
+
+		var out ReplicationStateResponse
+		err := replicationState(getter).Interact(context.Background(), Empty{}, &out)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"en": "v1"}, out.Dictionaries)
+		require.Equal(t, "en", out.Metadata.Aliases["main"])
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		t.Parallel()
+
+		var out ReplicationStateResponse
+		err := replicationState(&testReplicationStateGetter{err: errors.New("boom")}).Interact(context.Background(), Empty{}, &out)
+		require.Error(t, err)
+		require.True(t, err.(isErr).Is(status.Internal))
+	})
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/replication"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type replicationStatuser interface {
+	Status() replication.Status
+}
+
+type ReplicationStatusResponse struct {
+	Role        string     `json:"role" enum:"leader,follower" description:"The instance accepts writes (leader) or pulls the dictionaries from the leader (follower)."`
+	Leader      string     `json:"leader,omitempty" description:"URL of the leader."`
+	LastSync    *time.Time `json:"lastSync,omitempty" description:"The last time the follower caught up with the leader."`
+	LastAttempt *time.Time `json:"lastAttempt,omitempty" description:"The last time the follower polled the leader."`
+	Lag         float64    `json:"lag" description:"Seconds since the last sync (since the start if the follower has not synced yet)."`
+	Pending     int        `json:"pending" description:"Number of the changed dictionaries failed to download on the last attempt."`
+	Error       string     `json:"error,omitempty" description:"Error of the last attempt."`
+}
+
+// replicationStatus reports the lag of the follower, follower is nil on the leader
+func replicationStatus(follower replicationStatuser) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input Empty, output *ReplicationStatusResponse) error {
+		if follower == nil {
+			output.Role = "leader"
+			return nil
+		}
+
+		s := follower.Status()
+
+		*output = ReplicationStatusResponse{
+			Role:    "follower",
+			Leader:  s.Leader,
+			Lag:     s.Lag.Seconds(),
+			Pending: s.Pending,
+			Error:   s.Error,
+		}
+
+		if !s.LastSync.IsZero() {
+			output.LastSync = &s.LastSync
+		}
+
+		if !s.LastAttempt.IsZero() {
+			output.LastAttempt = &s.LastAttempt
+		}
+
+		return nil
+	})
+
+	u.SetTitle("Get the replication status")
+	u.SetDescription("Returns the role of the instance. A follower reports the replication lag and the error of the last attempt to sync with the leader.")
+	u.SetExpectedErrors(status.Internal)
+
+	return u
+}
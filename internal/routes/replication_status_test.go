@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/replication"
+	"github.com/stretchr/testify/require"
+)
+
+type testReplicationStatuser struct {
+	status replication.Status
+}
+
+func (f *testReplicationStatuser) Status() replication.Status {
+	return f.status
+}
+
+func Test_ReplicationStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("leader", func(t *testing.T) {
+		t.Parallel()
+
+		var out ReplicationStatusResponse
+		err := replicationStatus(nil).Interact(context.Background(), Empty{}, &out)
+		require.NoError(t, err)
+		require.Equal(t, ReplicationStatusResponse{Role: "leader"}, out)
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now().UTC()
+		follower := &testReplicationStatuser{status: replication.Status{
+			Leader:      "http://leader:8011",
+			LastAttempt: now,
+			Lag:         1500 * time.Millisecond,
+			Pending:     2,
+			Error:       "boom",
+		}}
+
+		var out ReplicationStatusResponse
+		err := replicationStatus(follower).Interact(context.Background(), Empty{}, &out)
+		require.NoError(t, err)
+		require.Equal(t, ReplicationStatusResponse{
+			Role:        "follower",
+			Leader:      "http://leader:8011",
+			LastAttempt: &now,
+			Lag:         1.5,
+			Pending:     2,
+			Error:       "boom",
+		}, out)
+	})
+}
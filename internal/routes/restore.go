@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/swaggest/rest/request"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+type restorer interface {
+	Restore(rd io.Reader, mode spellchecker.RestoreMode) (spellchecker.RestoreResult, error)
+}
+
+type RestoreRequest struct {
+	request.EmbeddedSetter
+
+	Mode string `query:"mode" default:"merge" enum:"merge,replace" description:"merge - the dictionaries and the aliases of the archive are added to the registry, existing ones with the same names are replaced; replace - the registry is replaced with the archive, dictionaries missing in the archive are deleted."`
+}
+
+type RestoreResponse struct {
+	Restored []string `json:"restored" description:"Dictionaries restored from the archive."`
+	Removed  []string `json:"removed,omitempty" description:"Dictionaries deleted by the replace mode."`
+	Aliases  int      `json:"aliases" description:"Number of aliases after the restore."`
+}
+
+// restore restores the registry, the request body is limited to limit bytes
+func restore(registry restorer, limit int64) usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input RestoreRequest, output *RestoreResponse) error {
+		body := http.MaxBytesReader(nil, input.Request().Body, limit)
+
+		var tooLarge *http.MaxBytesError
+
+		result, err := registry.Restore(body, spellchecker.RestoreMode(input.Mode))
+		if errors.Is(err, spellchecker.ErrInvalidBackup) || errors.As(err, &tooLarge) {
+			return status.Wrap(err, status.InvalidArgument)
+		} else if err != nil {
+			return status.Wrap(err, status.Internal)
+		}
+
+		output.Restored = result.Restored
+		output.Removed = result.Removed
+		output.Aliases = result.Aliases
+
+		return nil
+	})
+
+	u.SetTitle("Restore the registry")
+	u.SetDescription("Restores the registry from the archive (tar or tar.gz) made by GET /v1/_backup, the archive is passed as the request body. All the files of the archive are validated before the registry is changed. The archive must not exceed the restore limit, packed or unpacked.")
+	u.SetExpectedErrors(status.Internal, status.InvalidArgument)
+
+	return u
+}
@@ -0,0 +1,102 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/usecase/status"
+)
+
+type testRestorer struct {
+	body   string
+	mode   spellchecker.RestoreMode
+	result spellchecker.RestoreResult
+	err    error
+}
+
+func (f *testRestorer) Restore(rd io.Reader, mode spellchecker.RestoreMode) (spellchecker.RestoreResult, error) {
+	f.mode = mode
+
+	data, err := io.ReadAll(rd)
+	f.body = string(data)
+
+	if err != nil {
+		return spellchecker.RestoreResult{}, err
+	}
+
+	return f.result, f.err
+}
+
+func Test_Restore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		registry *testRestorer
+		mode     string
+		limit    int64
+		want     RestoreResponse
+		wantCode status.Code
+	}{
+		{
+			name:     "success",
+			registry: &testRestorer{result: spellchecker.RestoreResult{Restored: []string{"en"}, Removed: []string{"de"}, Aliases: 2}},
+			mode:     "replace",
+			limit:    100,
+			want:     RestoreResponse{Restored: []string{"en"}, Removed: []string{"de"}, Aliases: 2},
+		},
+		{
+			name:     "invalid backup",
+			registry: &testRestorer{err: fmt.Errorf("%w: unexpected file", spellchecker.ErrInvalidBackup)},
+			mode:     "merge",
+			limit:    100,
+			wantCode: status.InvalidArgument,
+		},
+		{
+			name:     "internal error",
+			registry: &testRestorer{err: errors.New("boom")},
+			mode:     "merge",
+			limit:    100,
+			wantCode: status.Internal,
+		},
+		{
+			name:     "too large",
+			registry: &testRestorer{},
+			mode:     "merge",
+			limit:    3,
+			wantCode: status.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := RestoreRequest{Mode: tt.mode}
+			input.SetRequest(httptest.NewRequest("POST", "/v1/_restore", strings.NewReader("archive")))
+
+			var out RestoreResponse
+			err := restore(tt.registry, tt.limit).Interact(context.Background(), input, &out)
+
+			// the body is cut at the limit
+			require.Equal(t, "archive"[:min(tt.limit, 7)], tt.registry.body)
+			require.Equal(t, spellchecker.RestoreMode(tt.mode), tt.registry.mode)
+
+			if tt.wantCode != status.OK {
+				require.Error(t, err)
+				require.True(t, err.(isErr).Is(tt.wantCode))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out)
+		})
+	}
+}
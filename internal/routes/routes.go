@@ -1,66 +1,307 @@
 package routes
 
 import (
+	"encoding/json"
 	"net/http"
 	"regexp"
 
+	"github.com/f1monkey/spellchecker-web/internal/cluster"
+	"github.com/f1monkey/spellchecker-web/internal/replication"
 	"github.com/f1monkey/spellchecker-web/internal/spellchecker"
 	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/rest"
 	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase/status"
 )
 
 type Empty struct{}
 
-func Routes(registry *spellchecker.Registry, splitter *regexp.Regexp) func(r chi.Router) {
+type Option func(c *config)
+
+type config struct {
+	leader   string // the routes changing dictionaries or aliases are redirected to the leader if set
+	follower replicationStatuser
+	cluster  *cluster.Cluster
+	readOnly bool // the routes changing dictionaries or aliases are rejected
+
+	restoreLimit int64 // max size of the restored archive
+}
+
+// WithFollower makes the instance a replication follower: the routes changing dictionaries or aliases are redirected to the leader,
+// unknown words are not collected as candidates
+func WithFollower(follower *replication.Follower) Option {
+	return func(c *config) {
+		c.leader = follower.Leader()
+		c.follower = follower
+	}
+}
+
+// WithCluster proxies the requests to the dictionaries and the aliases to the peers owning them
+func WithCluster(c *cluster.Cluster) Option {
+	return func(cfg *config) {
+		cfg.cluster = c
+	}
+}
+
+// WithReadOnly rejects the routes changing dictionaries or aliases with 403 Forbidden, unknown words are not collected as candidates
+func WithReadOnly() Option {
+	return func(c *config) {
+		c.readOnly = true
+	}
+}
+
+// WithRestoreLimit limits the size of the archive passed to the restore route, spellchecker.DefaultRestoreLimit by default
+func WithRestoreLimit(n int64) Option {
+	return func(c *config) {
+		c.restoreLimit = n
+	}
+}
+
+// proxy is the middleware of the dictionary and alias routes
+func (c config) proxy(next http.Handler) http.Handler {
+	if c.cluster == nil {
+		return next
+	}
+
+	return c.cluster.Proxy(next)
+}
+
+// remote returns the owner of the name in cluster mode if it is not the instance itself, nil if the cluster mode is disabled
+func (c config) remote() func(name string) string {
+	if c.cluster == nil {
+		return nil
+	}
+
+	return func(name string) string {
+		if owner := c.cluster.Owner(name); owner != c.cluster.Self() {
+			return owner
+		}
+
+		return ""
+	}
+}
+
+// collect reports if unknown words are collected as candidates. Followers do not collect them:
+// a collected candidate may be approved automatically, so the dictionary would differ from the leader.
+func (c config) collect() bool {
+	return !c.readOnly && c.leader == ""
+}
+
+// writes is the middleware of the routes changing dictionaries or aliases
+func (c config) writes(next http.Handler) http.Handler {
+	if c.readOnly {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			code, resp := rest.Err(status.Wrap(spellchecker.ErrReadOnly, status.PermissionDenied))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			_ = json.NewEncoder(w).Encode(resp)
+		})
+	}
+
+	if c.leader == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 307 keeps the method and the body of the request
+		http.Redirect(w, r, c.leader+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	})
+}
+
+func Routes(registry *spellchecker.Registry, splitter *regexp.Regexp, opts ...Option) func(r chi.Router) {
+	cfg := config{restoreLimit: spellchecker.DefaultRestoreLimit}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	return func(r chi.Router) {
+		r.Method(http.MethodPost, "/fix", nethttp.NewHandler(
+			fix(registry, splitter, cfg.collect(), cfg.remote()),
+		))
+
+		r.Method(http.MethodGet, "/_backup", nethttp.NewHandler(
+			backup(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/_restore", nethttp.NewHandler(
+			restore(registry, cfg.restoreLimit),
+		))
+
+		r.Method(http.MethodPost, "/_reload", nethttp.NewHandler(
+			reload(registry),
+		))
+
+		r.Route("/_replication", replicationRoutes(registry, cfg))
+
+		if cfg.cluster != nil {
+			r.Route("/_cluster", clusterRoutes(registry, cfg.cluster))
+		}
+
+		r.Route("/dictionaries", dictionaryRoutes(registry, splitter, cfg))
+		r.Route("/aliases", aliasRoutes(registry, cfg))
+	}
+}
+
+func replicationRoutes(registry *spellchecker.Registry, cfg config) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Method(http.MethodGet, "/state", nethttp.NewHandler(
+			replicationState(registry),
+		))
+
+		r.Method(http.MethodGet, "/status", nethttp.NewHandler(
+			replicationStatus(cfg.follower),
+		))
+
+		r.Method(http.MethodGet, "/dictionaries/{code}", nethttp.NewHandler(
+			replicationDictionary(registry),
+		))
+	}
+}
+
+func clusterRoutes(registry *spellchecker.Registry, c *cluster.Cluster) func(r chi.Router) {
 	return func(r chi.Router) {
-		r.Route("/dictionaries", dictionaryRoutes(registry, splitter))
-		r.Route("/aliases", aliasRoutes(registry))
+		r.Method(http.MethodGet, "/", nethttp.NewHandler(
+			clusterStatus(c),
+		))
+
+		r.Method(http.MethodPost, "/rebalance", nethttp.NewHandler(
+			clusterRebalance(c),
+		))
+
+		r.Method(http.MethodPut, "/dictionaries/{code}", nethttp.NewHandler(
+			clusterReceive(registry),
+		))
 	}
 }
 
-func dictionaryRoutes(registry *spellchecker.Registry, splitter *regexp.Regexp) func(r chi.Router) {
+func dictionaryRoutes(registry *spellchecker.Registry, splitter *regexp.Regexp, cfg config) func(r chi.Router) {
 	return func(r chi.Router) {
+		r.Use(cfg.proxy)
+
 		r.Method(http.MethodGet, "/", nethttp.NewHandler(
 			dictionaryList(registry),
 		))
 
-		r.Method(http.MethodPost, "/{code}", nethttp.NewHandler(
+		r.Method(http.MethodGet, "/_stats", nethttp.NewHandler(
+			dictionaryStats(registry),
+		))
+
+		r.Method(http.MethodGet, "/_failed", nethttp.NewHandler(
+			dictionaryFailed(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}", nethttp.NewHandler(
 			dictionaryCreate(registry),
 		))
 
-		r.Method(http.MethodDelete, "/{code}", nethttp.NewHandler(
+		r.With(cfg.writes).Method(http.MethodPatch, "/{code}", nethttp.NewHandler(
+			dictionaryUpdate(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodDelete, "/{code}", nethttp.NewHandler(
 			dictionaryDelete(registry),
 		))
 
-		r.Method(http.MethodPost, "/{code}/save", nethttp.NewHandler(
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}/save", nethttp.NewHandler(
 			dictionarySave(registry),
 		))
 
-		r.Method(http.MethodPost, "/{code}/add", nethttp.NewHandler(
+		r.Method(http.MethodPost, "/{code}/verify", nethttp.NewHandler(
+			dictionaryVerify(registry),
+		))
+
+		r.Method(http.MethodGet, "/{code}/versions", nethttp.NewHandler(
+			dictionaryVersionList(registry),
+		))
+
+		r.Method(http.MethodGet, "/{code}/versions/diff", nethttp.NewHandler(
+			dictionaryVersionDiff(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}/versions/{version}/rollback", nethttp.NewHandler(
+			dictionaryVersionRollback(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}/add", nethttp.NewHandler(
 			dictionaryItemAdd(registry, splitter),
 		))
 
 		r.Method(http.MethodPost, "/{code}/fix", nethttp.NewHandler(
-			dictionaryFix(registry, splitter),
+			dictionaryFix(registry, splitter, cfg.collect()),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}/feedback", nethttp.NewHandler(
+			dictionaryFeedback(registry),
+		))
+
+		r.Method(http.MethodGet, "/{code}/candidates", nethttp.NewHandler(
+			dictionaryCandidateList(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}/candidates/{word}/approve", nethttp.NewHandler(
+			dictionaryCandidateApprove(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{code}/candidates/{word}/reject", nethttp.NewHandler(
+			dictionaryCandidateReject(registry),
+		))
+
+		r.Method(http.MethodGet, "/{code}/rules", nethttp.NewHandler(
+			dictionaryRuleList(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPut, "/{code}/rules/{id}", nethttp.NewHandler(
+			dictionaryRuleSet(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodDelete, "/{code}/rules/{id}", nethttp.NewHandler(
+			dictionaryRuleDelete(registry),
+		))
+
+		r.Method(http.MethodGet, "/{code}/forbidden", nethttp.NewHandler(
+			dictionaryForbiddenList(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPut, "/{code}/forbidden/{word}", nethttp.NewHandler(
+			dictionaryForbiddenSet(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodDelete, "/{code}/forbidden/{word}", nethttp.NewHandler(
+			dictionaryForbiddenDelete(registry),
 		))
 	}
 }
 
-func aliasRoutes(registry *spellchecker.Registry) func(r chi.Router) {
+func aliasRoutes(registry *spellchecker.Registry, cfg config) func(r chi.Router) {
 	return func(r chi.Router) {
+		r.Use(cfg.proxy)
+
 		r.Method(http.MethodGet, "/", nethttp.NewHandler(
 			aliasList(registry),
 		))
 
+		r.With(cfg.writes).Method(http.MethodPost, "/_swap", nethttp.NewHandler(
+			aliasSwap(registry),
+		))
+
 		r.Method(http.MethodGet, "/{alias}", nethttp.NewHandler(
 			aliasGet(registry),
 		))
 
-		r.Method(http.MethodPut, "/{alias}", nethttp.NewHandler(
+		r.Method(http.MethodGet, "/{alias}/history", nethttp.NewHandler(
+			aliasHistory(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPost, "/{alias}/rollback", nethttp.NewHandler(
+			aliasRollback(registry),
+		))
+
+		r.With(cfg.writes).Method(http.MethodPut, "/{alias}", nethttp.NewHandler(
 			aliasSet(registry),
 		))
 
-		r.Method(http.MethodDelete, "/{alias}", nethttp.NewHandler(
+		r.With(cfg.writes).Method(http.MethodDelete, "/{alias}", nethttp.NewHandler(
 			aliasDelete(registry),
 		))
 	}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_config_writes(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("leader", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		config{}.writes(next).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/dictionaries/en/add", nil))
+		require.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		config{leader: "http://leader:8011"}.writes(next).ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/v1/aliases/main?x=1", nil))
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		require.Equal(t, "http://leader:8011/v1/aliases/main?x=1", w.Header().Get("Location"))
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		config{readOnly: true}.writes(next).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/dictionaries/en/add", nil))
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.JSONEq(t, `{"status":"PERMISSION_DENIED","error":"permission denied: registry is read-only"}`, w.Body.String())
+	})
+}
+
+func Test_config_collect(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, config{}.collect())
+	require.False(t, config{leader: "http://leader:8011"}.collect())
+	require.False(t, config{readOnly: true}.collect())
+}
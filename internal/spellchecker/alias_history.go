@@ -0,0 +1,175 @@
+package spellchecker
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// maxAliasLog is the max number of alias changes kept in the log
+const maxAliasLog = 1000
+
+var (
+	ErrNoPreviousTarget = fmt.Errorf("alias has no previous target")
+)
+
+// AliasChange is a record of the alias log. From is empty if the alias was created, To is empty if it was deleted.
+// Targets are set if the alias was pointed to several targets (To is the first one).
+type AliasChange struct {
+	Alias   string        `json:"alias"`
+	From    string        `json:"from,omitempty"`
+	To      string        `json:"to,omitempty"`
+	Targets []AliasTarget `json:"targets,omitempty"`
+	Time    time.Time     `json:"time"`
+}
+
+// SwapAliases points all the aliases (alias => dictionary) to their dictionaries at once.
+// Nothing is changed if any of the dictionaries does not exist, the swap makes an alias chain cyclic or the metadata can not be saved.
+func (r *Registry) SwapAliases(aliases map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for alias, to := range aliases {
+		if err := r.doCheckTargets(alias, []AliasTarget{{Dictionary: to}}, ErrNotFound); err != nil {
+			return err
+		}
+	}
+
+	prev := r.metadata.clone()
+	now := time.Now().UTC()
+
+	// sorted to keep the log order stable
+	names := slices.Sorted(maps.Keys(aliases))
+
+	changed := false
+	for _, alias := range names {
+		changed = r.doSetAlias(alias, []AliasTarget{{Dictionary: aliases[alias]}}, now) || changed
+	}
+
+	// the targets are checked against the aliases before the swap, the swapped aliases may point to each other
+	for _, alias := range names {
+		if reaches(r.metadata, alias, alias, r.doExists, 0) {
+			r.metadata = prev
+			return fmt.Errorf("%w: %q", ErrAliasCycle, alias)
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := r.doSaveMetadata(); err != nil {
+		r.metadata = prev
+		return err
+	}
+
+	return nil
+}
+
+// RollbackAlias points the alias to the dictionary (or the targets) it pointed to before the last change.
+// A deleted alias is restored.
+func (r *Registry) RollbackAlias(alias string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	targets, ok := r.doPreviousTargets(alias)
+	if !ok {
+		return "", ErrAliasNotFound
+	}
+
+	if len(targets) == 0 {
+		return "", ErrNoPreviousTarget
+	}
+
+	if err := r.doCheckTargets(alias, targets, ErrNotFound); err != nil {
+		return "", err
+	}
+
+	prev := r.metadata.clone()
+
+	r.doSetAlias(alias, targets, time.Now().UTC())
+
+	if err := r.doSaveMetadata(); err != nil {
+		r.metadata = prev
+		return "", err
+	}
+
+	return targets[0].Dictionary, nil
+}
+
+// AliasHistory returns changes of the alias, the newest first
+func (r *Registry) AliasHistory(alias string) []AliasChange {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]AliasChange, 0)
+	for i := len(r.metadata.Log) - 1; i >= 0; i-- {
+		if r.metadata.Log[i].Alias == alias {
+			result = append(result, r.metadata.Log[i])
+		}
+	}
+
+	return result
+}
+
+// doPreviousTargets returns the targets of the alias before the last change. Returns false if the alias was never changed.
+func (r *Registry) doPreviousTargets(alias string) ([]AliasTarget, bool) {
+	var last *AliasChange
+
+	for i := len(r.metadata.Log) - 1; i >= 0; i-- {
+		change := &r.metadata.Log[i]
+		if change.Alias != alias {
+			continue
+		}
+
+		if last == nil {
+			last = change
+			continue
+		}
+
+		// the change before the last one knows all the targets the alias had
+		if change.To == last.From && len(change.Targets) > 0 {
+			return slices.Clone(change.Targets), true
+		}
+
+		break
+	}
+
+	if last == nil {
+		return nil, false
+	}
+
+	if last.From == "" {
+		return nil, true
+	}
+
+	return []AliasTarget{{Dictionary: last.From}}, true
+}
+
+func (r *Registry) doLog(change AliasChange) {
+	r.metadata.Log = append(r.metadata.Log, change)
+
+	if len(r.metadata.Log) > maxAliasLog {
+		r.metadata.Log = slices.Clone(r.metadata.Log[len(r.metadata.Log)-maxAliasLog:])
+	}
+}
+
+func (m Metadata) clone() Metadata {
+	result := Metadata{
+		Aliases:         maps.Clone(m.Aliases),
+		InvertedAliases: make(map[string][]string, len(m.InvertedAliases)),
+		Splits:          make(map[string][]AliasTarget, len(m.Splits)),
+		Log:             slices.Clone(m.Log),
+	}
+
+	for code, aliases := range m.InvertedAliases {
+		result.InvertedAliases[code] = slices.Clone(aliases)
+	}
+
+	for alias, targets := range m.Splits {
+		result.Splits[alias] = slices.Clone(targets)
+	}
+
+	return result
+}
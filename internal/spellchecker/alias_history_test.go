@@ -0,0 +1,172 @@
+package spellchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_SwapAliases(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		for _, code := range []string{"en-v1", "en-v2", "medical-v1", "medical-v2"} {
+			_, err = r.Add(code, Options{Alphabet: "abc"})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, r.SetAlias("prod-en", "en-v1"))
+		require.NoError(t, r.SetAlias("prod-en-medical", "medical-v1"))
+
+		return r
+	}
+
+	t.Run("dictionary not found", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		err := r.SwapAliases(map[string]string{"prod-en": "en-v2", "prod-en-medical": "qwerty"})
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.Equal(t, "en-v1", r.metadata.Aliases["prod-en"])
+		require.Equal(t, "medical-v1", r.metadata.Aliases["prod-en-medical"])
+		require.Len(t, r.metadata.Log, 2)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+		require.NoError(t, r.SetAlias("staging-en", "en-v1"))
+
+		// each alias exists before the swap, but they point to each other after it
+		err := r.SwapAliases(map[string]string{"prod-en": "staging-en", "staging-en": "prod-en"})
+		require.ErrorIs(t, err, ErrAliasCycle)
+
+		require.Equal(t, "en-v1", r.metadata.Aliases["prod-en"])
+		require.Equal(t, "en-v1", r.metadata.Aliases["staging-en"])
+
+		code, _, err := r.ResolveItem("prod-en", "")
+		require.NoError(t, err)
+		require.Equal(t, "en-v1", code)
+		require.Len(t, r.metadata.Log, 3)
+	})
+
+	t.Run("metadata save error", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+		r.storage = failingStorage{Storage: r.storage}
+
+		err := r.SwapAliases(map[string]string{"prod-en": "en-v2"})
+		require.Error(t, err)
+		require.Equal(t, "en-v1", r.metadata.Aliases["prod-en"])
+		require.Len(t, r.metadata.Log, 2)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		err := r.SwapAliases(map[string]string{"prod-en": "en-v2", "prod-en-medical": "medical-v2", "staging-en": "en-v1"})
+		require.NoError(t, err)
+
+		require.Equal(t, "en-v2", r.metadata.Aliases["prod-en"])
+		require.Equal(t, "medical-v2", r.metadata.Aliases["prod-en-medical"])
+		require.Equal(t, "en-v1", r.metadata.Aliases["staging-en"])
+		require.Equal(t, []string{"staging-en"}, r.metadata.InvertedAliases["en-v1"])
+		require.Equal(t, []string{"prod-en"}, r.metadata.InvertedAliases["en-v2"])
+
+		require.Len(t, r.metadata.Log, 5)
+		require.Equal(t, AliasChange{Alias: "prod-en", From: "en-v1", To: "en-v2", Time: r.metadata.Log[2].Time}, r.metadata.Log[2])
+
+		// saved to disk
+		require.NoError(t, r.SaveAll(context.Background()))
+
+		loaded := reopen(t, r)
+		require.Equal(t, r.metadata, loaded.metadata)
+	})
+}
+
+func Test_Registry_RollbackAlias(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		for _, code := range []string{"v1", "v2"} {
+			_, err = r.Add(code, Options{Alphabet: "abc"})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, r.SetAlias("prod", "v1"))
+
+		return r
+	}
+
+	t.Run("unknown alias", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		_, err := r.RollbackAlias("qwerty")
+		require.ErrorIs(t, err, ErrAliasNotFound)
+	})
+
+	t.Run("no previous target", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		_, err := r.RollbackAlias("prod")
+		require.ErrorIs(t, err, ErrNoPreviousTarget)
+	})
+
+	t.Run("previous dictionary deleted", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+		require.NoError(t, r.SetAlias("prod", "v2"))
+		require.NoError(t, r.Delete("v1", false))
+
+		_, err := r.RollbackAlias("prod")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Equal(t, "v2", r.metadata.Aliases["prod"])
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+		require.NoError(t, r.SetAlias("prod", "v2"))
+
+		to, err := r.RollbackAlias("prod")
+		require.NoError(t, err)
+		require.Equal(t, "v1", to)
+		require.Equal(t, "v1", r.metadata.Aliases["prod"])
+
+		history := r.AliasHistory("prod")
+		require.Len(t, history, 3)
+		require.Equal(t, "v2", history[0].From)
+		require.Equal(t, "v1", history[0].To)
+	})
+
+	t.Run("deleted alias", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+		require.NoError(t, r.DeleteAlias("prod"))
+
+		to, err := r.RollbackAlias("prod")
+		require.NoError(t, err)
+		require.Equal(t, "v1", to)
+		require.Equal(t, "v1", r.metadata.Aliases["prod"])
+	})
+}
@@ -0,0 +1,223 @@
+package spellchecker
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+)
+
+// maxAliasDepth is the max length of an alias chain
+const maxAliasDepth = 8
+
+var (
+	ErrAliasCycle          = fmt.Errorf("alias chain is cyclic or too long")
+	ErrInvalidAliasTargets = fmt.Errorf("invalid alias targets")
+)
+
+// AliasTarget is a dictionary (or an alias) the alias points to. Weight is a share of requests routed to the target.
+type AliasTarget struct {
+	Dictionary string `json:"dictionary"`
+	Weight     uint   `json:"weight,omitempty"`
+}
+
+type Alias struct {
+	Dictionary string
+	Targets    []AliasTarget // only for aliases with several targets
+}
+
+func (r *Registry) GetAlias(alias string) (Alias, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.metadata.Aliases[alias]
+	if !ok {
+		return Alias{}, ErrAliasNotFound
+	}
+
+	return Alias{Dictionary: v, Targets: slices.Clone(r.metadata.Splits[alias])}, nil
+}
+
+// ResolveItem returns the dictionary by its code or alias along with the code of the dictionary.
+// Aliases with several targets choose one by the weights: randomly if the key is empty,
+// otherwise the same key is always routed to the same target. The dictionary must be released after use.
+func (r *Registry) ResolveItem(name string, key string) (string, RegistryItem, error) {
+	return r.resolve(name, func(alias string, targets []AliasTarget) string {
+		return pickTarget(alias, targets, key)
+	})
+}
+
+// resolve is the same as doResolve, but loads the dictionary if it is not loaded yet
+func (r *Registry) resolve(name string, choose func(alias string, targets []AliasTarget) string) (string, RegistryItem, error) {
+	r.mu.RLock()
+	code, item, err := r.doResolve(name, choose)
+	if err == nil {
+		item = r.doPin(item)
+	}
+	r.mu.RUnlock()
+
+	if errors.Is(err, errNotLoaded) {
+		item, err = r.load(code)
+		return code, item, err
+	} else if err != nil {
+		return "", RegistryItem{}, err
+	}
+
+	r.cache.touch(code)
+	r.evictOver(code)
+
+	return code, item, nil
+}
+
+// doResolve follows the alias chain to a dictionary, choose picks a target of an alias with several targets.
+// errNotLoaded is returned along with the code if the dictionary is not loaded yet.
+func (r *Registry) doResolve(name string, choose func(alias string, targets []AliasTarget) string) (string, RegistryItem, error) {
+	for range maxAliasDepth + 1 {
+		if item, ok := r.items[name]; ok {
+			return name, item, nil
+		}
+
+		if _, ok := r.unloaded[name]; ok {
+			return name, RegistryItem{}, errNotLoaded
+		}
+
+		to, ok := r.metadata.Aliases[name]
+		if !ok {
+			return "", RegistryItem{}, ErrNotFound
+		}
+
+		if targets, ok := r.metadata.Splits[name]; ok {
+			to = choose(name, targets)
+		}
+
+		name = to
+	}
+
+	return "", RegistryItem{}, ErrAliasCycle
+}
+
+// doCheckTargets validates the targets of the alias. notFound is returned if a target does not exist.
+// The alias can not have the name of a dictionary, the dictionary would always be resolved instead of it.
+func (r *Registry) doCheckTargets(alias string, targets []AliasTarget, notFound error) error {
+	if r.doExists(alias) {
+		return fmt.Errorf("%w: alias %q has the name of the dictionary", ErrAlreadyExists, alias)
+	}
+
+	if err := checkTargets(targets); err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		if !r.doTargetExists(t.Dictionary) {
+			return fmt.Errorf("%w: %q", notFound, t.Dictionary)
+		}
+
+		if r.doReaches(t.Dictionary, alias, 1) {
+			return fmt.Errorf("%w: %q", ErrAliasCycle, t.Dictionary)
+		}
+	}
+
+	return nil
+}
+
+// checkTargets validates the targets regardless of the registry: several targets must have weights and must not repeat
+func checkTargets(targets []AliasTarget) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: no targets", ErrInvalidAliasTargets)
+	}
+
+	seen := make(map[string]struct{}, len(targets))
+
+	for _, t := range targets {
+		if len(targets) > 1 && t.Weight == 0 {
+			return fmt.Errorf("%w: zero weight of %q", ErrInvalidAliasTargets, t.Dictionary)
+		}
+
+		if _, ok := seen[t.Dictionary]; ok {
+			return fmt.Errorf("%w: duplicate target %q", ErrInvalidAliasTargets, t.Dictionary)
+		}
+		seen[t.Dictionary] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *Registry) doTargetExists(name string) bool {
+	if r.doExists(name) {
+		return true
+	}
+
+	_, ok := r.metadata.Aliases[name]
+
+	return ok
+}
+
+// doReaches checks if the alias chain starting from name reaches the alias or is too long
+func (r *Registry) doReaches(name string, alias string, depth int) bool {
+	if name == alias || depth > maxAliasDepth {
+		return true
+	}
+
+	if r.doExists(name) {
+		return false
+	}
+
+	for _, t := range r.doAliasTargets(name) {
+		if r.doReaches(t.Dictionary, alias, depth+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// doAliasTargets returns the targets of the alias (a single target without weight for an ordinary alias)
+func (r *Registry) doAliasTargets(alias string) []AliasTarget {
+	return r.metadata.targets(alias)
+}
+
+func (m Metadata) targets(alias string) []AliasTarget {
+	if targets, ok := m.Splits[alias]; ok {
+		return targets
+	}
+
+	if to, ok := m.Aliases[alias]; ok {
+		return []AliasTarget{{Dictionary: to}}
+	}
+
+	return nil
+}
+
+// pickTarget chooses a target by the weights. The choice is random if the key is empty.
+// The first target is chosen if no target has a weight.
+func pickTarget(alias string, targets []AliasTarget, key string) string {
+	var total uint64
+	for _, t := range targets {
+		total += uint64(t.Weight)
+	}
+
+	if total == 0 {
+		return targets[0].Dictionary
+	}
+
+	var n uint64
+	if key == "" {
+		n = rand.Uint64N(total)
+	} else {
+		h := fnv.New64a()
+		h.Write([]byte(alias))
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		n = h.Sum64() % total
+	}
+
+	for _, t := range targets {
+		if n < uint64(t.Weight) {
+			return t.Dictionary
+		}
+		n -= uint64(t.Weight)
+	}
+
+	return targets[len(targets)-1].Dictionary
+}
@@ -0,0 +1,253 @@
+package spellchecker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_SetAliasTargets(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		for _, code := range []string{"a", "b"} {
+			_, err = r.Add(code, Options{Alphabet: "abc"})
+			require.NoError(t, err)
+		}
+
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		alias   string
+		targets []AliasTarget
+		wantErr error
+	}{
+		{name: "no targets", alias: "ab", wantErr: ErrInvalidAliasTargets},
+		{name: "zero weight", alias: "ab", targets: []AliasTarget{{Dictionary: "a", Weight: 1}, {Dictionary: "b"}}, wantErr: ErrInvalidAliasTargets},
+		{name: "duplicate", alias: "ab", targets: []AliasTarget{{Dictionary: "a", Weight: 1}, {Dictionary: "a", Weight: 1}}, wantErr: ErrInvalidAliasTargets},
+		{name: "not found", alias: "ab", targets: []AliasTarget{{Dictionary: "a", Weight: 1}, {Dictionary: "c", Weight: 1}}, wantErr: ErrAliasNotFound},
+		{name: "dictionary name", alias: "b", targets: []AliasTarget{{Dictionary: "a"}}, wantErr: ErrAlreadyExists},
+		{name: "self", alias: "ab", targets: []AliasTarget{{Dictionary: "ab"}}, wantErr: ErrAliasNotFound},
+		{name: "success", alias: "ab", targets: []AliasTarget{{Dictionary: "a", Weight: 90}, {Dictionary: "b", Weight: 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newRegistry(t)
+
+			err := r.SetAliasTargets(tt.alias, tt.targets)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.NotContains(t, r.metadata.Aliases, tt.alias)
+				return
+			}
+
+			require.NoError(t, err)
+
+			alias, err := r.GetAlias(tt.alias)
+			require.NoError(t, err)
+			require.Equal(t, Alias{Dictionary: "a", Targets: tt.targets}, alias)
+			require.Equal(t, []string{"ab"}, r.metadata.InvertedAliases["a"])
+			require.Equal(t, []string{"ab"}, r.metadata.InvertedAliases["b"])
+
+			// a dictionary used by a split alias is protected from deletion
+			require.ErrorIs(t, r.Delete("b", false), ErrHasAliases)
+
+			// an ordinary alias replaces the split one
+			require.NoError(t, r.SetAlias(tt.alias, "b"))
+			require.Empty(t, r.metadata.Splits)
+			require.NotContains(t, r.metadata.InvertedAliases, "a")
+		})
+	}
+}
+
+func Test_Registry_AliasChains(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	_, err = r.Add("en-v1", Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	require.NoError(t, r.SetAlias("en", "en-v1"))
+	require.NoError(t, r.SetAlias("prod-en", "en"))
+
+	code, _, err := r.ResolveItem("prod-en", "")
+	require.NoError(t, err)
+	require.Equal(t, "en-v1", code)
+
+	_, err = r.GetItem("prod-en")
+	require.NoError(t, err)
+
+	// cycles are not allowed
+	err = r.SetAlias("en", "prod-en")
+	require.ErrorIs(t, err, ErrAliasCycle)
+
+	// too long chains are not allowed either
+	prev := "prod-en"
+	for i := range maxAliasDepth {
+		name := fmt.Sprintf("chain-%d", i)
+
+		err = r.SetAlias(name, prev)
+		if err != nil {
+			require.ErrorIs(t, err, ErrAliasCycle)
+			break
+		}
+
+		prev = name
+	}
+	require.Error(t, err)
+
+	// an alias used by other aliases can not be deleted
+	require.ErrorIs(t, r.DeleteAlias("en"), ErrHasAliases)
+
+	// cascade deletion removes the whole chain
+	require.NoError(t, r.Delete("en-v1", true))
+	require.Empty(t, r.metadata.Aliases)
+	require.Empty(t, r.metadata.InvertedAliases)
+}
+
+func Test_Registry_Delete_SplitAlias(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	for _, code := range []string{"a", "b", "c"} {
+		_, err = r.Add(code, Options{Alphabet: "abc"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.SetAliasTargets("abc", []AliasTarget{{Dictionary: "a", Weight: 3}, {Dictionary: "b", Weight: 2}, {Dictionary: "c", Weight: 1}}))
+	require.NoError(t, r.SetAlias("prod", "abc"))
+
+	// only the removed dictionary is removed from the targets
+	require.NoError(t, r.Delete("a", true))
+
+	alias, err := r.GetAlias("abc")
+	require.NoError(t, err)
+	require.Equal(t, "b", alias.Dictionary)
+	require.Equal(t, []AliasTarget{{Dictionary: "b", Weight: 2}, {Dictionary: "c", Weight: 1}}, alias.Targets)
+	require.NotContains(t, r.metadata.InvertedAliases, "a")
+
+	// the alias with a single target left becomes an ordinary one
+	require.NoError(t, r.Delete("b", true))
+
+	alias, err = r.GetAlias("abc")
+	require.NoError(t, err)
+	require.Equal(t, Alias{Dictionary: "c"}, alias)
+
+	code, _, err := r.ResolveItem("prod", "")
+	require.NoError(t, err)
+	require.Equal(t, "c", code)
+
+	// the alias without targets is removed along with the chain
+	require.NoError(t, r.Delete("c", true))
+	require.Empty(t, r.metadata.Aliases)
+	require.Empty(t, r.metadata.InvertedAliases)
+	require.Empty(t, r.metadata.Splits)
+}
+
+func Test_Registry_ResolveItem(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	for _, code := range []string{"a", "b"} {
+		_, err = r.Add(code, Options{Alphabet: "abc"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.SetAliasTargets("ab", []AliasTarget{{Dictionary: "a", Weight: 3}, {Dictionary: "b", Weight: 1}}))
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := r.ResolveItem("qwerty", "")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("dictionary", func(t *testing.T) {
+		t.Parallel()
+
+		code, item, err := r.ResolveItem("b", "key")
+		require.NoError(t, err)
+		require.Equal(t, "b", code)
+		require.NotNil(t, item.Spellchecker)
+	})
+
+	t.Run("split by weights", func(t *testing.T) {
+		t.Parallel()
+
+		counts := map[string]int{}
+		for i := range 1000 {
+			code, _, err := r.ResolveItem("ab", fmt.Sprintf("user-%d", i))
+			require.NoError(t, err)
+			counts[code]++
+		}
+
+		require.InDelta(t, 750, counts["a"], 100)
+		require.InDelta(t, 250, counts["b"], 100)
+	})
+
+	t.Run("sticky key", func(t *testing.T) {
+		t.Parallel()
+
+		first, _, err := r.ResolveItem("ab", "user-1")
+		require.NoError(t, err)
+
+		for range 20 {
+			code, _, err := r.ResolveItem("ab", "user-1")
+			require.NoError(t, err)
+			require.Equal(t, first, code)
+		}
+	})
+
+	t.Run("no weights", func(t *testing.T) {
+		t.Parallel()
+
+		targets := []AliasTarget{{Dictionary: "a"}, {Dictionary: "b"}}
+		require.Equal(t, "a", pickTarget("ab", targets, ""))
+		require.Equal(t, "a", pickTarget("ab", targets, "key"))
+	})
+
+	t.Run("get item uses the first target", func(t *testing.T) {
+		t.Parallel()
+
+		item, err := r.GetItem("ab")
+		require.NoError(t, err)
+		require.Same(t, r.items["a"].Spellchecker, item.Spellchecker)
+	})
+}
+
+func Test_Registry_RollbackAlias_Split(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	for _, code := range []string{"a", "b"} {
+		_, err = r.Add(code, Options{Alphabet: "abc"})
+		require.NoError(t, err)
+	}
+
+	targets := []AliasTarget{{Dictionary: "a", Weight: 1}, {Dictionary: "b", Weight: 1}}
+	require.NoError(t, r.SetAliasTargets("ab", targets))
+	require.NoError(t, r.SetAlias("ab", "b"))
+
+	to, err := r.RollbackAlias("ab")
+	require.NoError(t, err)
+	require.Equal(t, "a", to)
+	require.Equal(t, targets, r.metadata.Splits["ab"])
+}
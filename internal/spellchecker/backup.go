@@ -0,0 +1,610 @@
+package spellchecker
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/storage"
+)
+
+var (
+	ErrInvalidBackup  = errors.New("invalid backup")
+	ErrBackupTooLarge = errors.New("backup is too large")
+)
+
+const (
+	// restoreDir keeps the files of the backup before they are renamed, and the replaced files until the restore is done
+	restoreDir  = "restore"
+	replacedDir = "replaced"
+
+	// DefaultRestoreLimit is the max size of the unpacked archive accepted by Restore
+	DefaultRestoreLimit = 4 << 30
+)
+
+// WithRestoreLimit limits the size of the unpacked archive accepted by Restore, a gzipped archive may be much smaller
+func WithRestoreLimit(n int64) RegistryOption {
+	return func(r *Registry) {
+		r.restoreLimit = n
+	}
+}
+
+type RestoreMode string
+
+const (
+	// RestoreMerge adds the dictionaries and the aliases of the backup to the registry, existing ones are replaced
+	RestoreMerge RestoreMode = "merge"
+	// RestoreReplace replaces the whole registry with the backup, dictionaries missing in the backup are deleted
+	RestoreReplace RestoreMode = "replace"
+)
+
+type RestoreResult struct {
+	Restored []string // restored dictionaries
+	Removed  []string // dictionaries deleted by RestoreReplace
+	Aliases  int      // number of aliases after the restore
+}
+
+// backupFile is a file of the backup kept in a temporary directory
+type backupFile struct {
+	name string // name in the archive
+	path string
+}
+
+// Backup writes a tar archive (gzipped if compress is true) with the dictionaries and the metadata.
+// The files are taken under the registry lock, so the archive is consistent. The lock is released before streaming.
+func (r *Registry) Backup(w io.Writer, compress bool) error {
+	dir, err := os.MkdirTemp("", "spellchecker-backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	files, err := r.snapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	if compress {
+		gw := gzip.NewWriter(w)
+		if err := writeTar(gw, files); err != nil {
+			return err
+		}
+
+		return gw.Close()
+	}
+
+	return writeTar(w, files)
+}
+
+func (r *Registry) snapshotFiles(dir string) ([]backupFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	metadata, err := json.Marshal(r.metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []backupFile{{name: storage.MetadataFile, path: path.Join(dir, storage.MetadataFile)}}
+	if err := os.WriteFile(result[0].path, encodeFile(metadata), 0644); err != nil {
+		return nil, err
+	}
+
+	codes := slices.Sorted(maps.Keys(r.items))
+	codes = append(codes, slices.Sorted(maps.Keys(r.unloaded))...)
+
+	for _, code := range codes {
+		f := backupFile{name: fileName(code), path: path.Join(dir, fileName(code))}
+
+		if err := r.doBackupFile(code, f.path); err != nil {
+			return nil, fmt.Errorf("dictionary %q: %w", code, err)
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+// doBackupFile writes the dictionary to the local file, the file of the storage is copied if the dictionary is not loaded
+func (r *Registry) doBackupFile(code string, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if item, ok := r.items[code]; ok {
+		_, err = writeItem(f, item, r.compression)
+		return err
+	}
+
+	src, err := r.storage.Open(fileName(code))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(f, src)
+
+	return err
+}
+
+func writeTar(w io.Writer, files []backupFile) error {
+	tw := tar.NewWriter(w)
+	now := time.Now().UTC()
+
+	for _, f := range files {
+		if err := writeTarFile(tw, f, now); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, f backupFile, modTime time.Time) error {
+	src, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     f.name,
+		Size:     info.Size(),
+		Mode:     0644,
+		ModTime:  modTime,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, src)
+
+	return err
+}
+
+// restoredDictionary is a dictionary of the backup validated before the restore
+type restoredDictionary struct {
+	path    string
+	item    RegistryItem // not kept with lazy loading
+	options Options
+	info    FileInfo
+}
+
+type backup struct {
+	dictionaries map[string]restoredDictionary
+	metadata     Metadata
+}
+
+// Restore restores the registry from the archive written by Backup.
+// All the files of the archive are validated and written to the storage under temporary names before the registry is changed,
+// then the files are renamed. An invalid archive or a failed write changes nothing, the renamed files are moved back on failure.
+func (r *Registry) Restore(rd io.Reader, mode RestoreMode) (RestoreResult, error) {
+	if mode != RestoreMerge && mode != RestoreReplace {
+		return RestoreResult{}, fmt.Errorf("unknown restore mode %q", mode)
+	}
+
+	dir, err := os.MkdirTemp("", "spellchecker-restore-*")
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := r.readBackup(rd, dir)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	// the files are staged before the registry is locked, only renames are left
+	staging := path.Join(restoreDir, path.Base(dir))
+	defer r.removeStaged(staging, slices.Collect(maps.Keys(b.dictionaries)))
+
+	for _, code := range slices.Sorted(maps.Keys(b.dictionaries)) {
+		if err := r.writeFile(path.Join(staging, fileName(code)), b.dictionaries[code].path); err != nil {
+			return RestoreResult{}, fmt.Errorf("dictionary %q: %w", code, err)
+		}
+	}
+
+	// a dictionary being loaded must not be inserted after the restore
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev := r.metadata
+
+	exists := func(code string) bool {
+		_, ok := b.dictionaries[code]
+
+		return ok || mode == RestoreMerge && r.doExists(code)
+	}
+
+	if mode == RestoreReplace {
+		r.metadata = b.metadata
+	} else {
+		r.metadata = prev.clone()
+		now := time.Now().UTC()
+
+		for _, alias := range slices.Sorted(maps.Keys(b.metadata.Aliases)) {
+			r.doSetAlias(alias, b.metadata.targets(alias), now)
+		}
+	}
+
+	if err := checkAliases(r.metadata, exists); err != nil {
+		r.metadata = prev
+		return RestoreResult{}, err
+	}
+
+	var result RestoreResult
+
+	if mode == RestoreReplace {
+		for _, code := range slices.Sorted(maps.Keys(r.items)) {
+			if !exists(code) {
+				result.Removed = append(result.Removed, code)
+			}
+		}
+
+		for _, code := range slices.Sorted(maps.Keys(r.unloaded)) {
+			if !exists(code) {
+				result.Removed = append(result.Removed, code)
+			}
+		}
+
+		slices.Sort(result.Removed)
+	}
+
+	moved, err := r.doCommitRestore(staging, b, result.Removed)
+	if err != nil {
+		r.doRollbackRestore(moved)
+		r.metadata = prev
+
+		return RestoreResult{}, err
+	}
+
+	for _, code := range result.Removed {
+		delete(r.items, code)
+		delete(r.unloaded, code)
+		r.cache.remove(code, false)
+		r.forgetChecksum(code)
+
+		// the versions of a deleted dictionary are not used anymore, so a failure only leaves garbage
+		if err := r.doDeleteVersions(code); err != nil {
+			r.log.Error("registry: restore cleanup error", "dictionary", code, "error", err)
+		}
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(b.dictionaries)) {
+		// the logs are already removed by the commit
+		if err := r.doRestored(code, b.dictionaries[code]); err != nil {
+			r.log.Error("registry: restore cleanup error", "dictionary", code, "error", err)
+		}
+
+		result.Restored = append(result.Restored, code)
+	}
+
+	// the replaced files are kept until the restore is committed
+	for _, m := range moved {
+		if !strings.HasPrefix(m.from, staging) {
+			if err := r.storage.Delete(m.to); err != nil {
+				r.log.Error("registry: restore cleanup error", "file", m.to, "error", err)
+			}
+		}
+	}
+
+	result.Aliases = len(r.metadata.Aliases)
+
+	r.log.Info("registry: restored from backup", "mode", mode, "restored", len(result.Restored), "removed", len(result.Removed))
+
+	return result, nil
+}
+
+// restoreMove is a rename done by the restore, it is reverted if the restore fails
+type restoreMove struct {
+	from string
+	to   string
+}
+
+// doCommitRestore removes the operations logs of the replaced and removed dictionaries, moves their files aside,
+// moves the staged files in place and saves the metadata. Returns the renames done so far.
+func (r *Registry) doCommitRestore(staging string, b backup, removed []string) ([]restoreMove, error) {
+	replaced := append(slices.Sorted(maps.Keys(b.dictionaries)), removed...)
+
+	// the log contains the changes of the replaced dictionary
+	for _, code := range replaced {
+		if err := r.doRemoveLog(code); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []restoreMove
+
+	for _, code := range replaced {
+		m := restoreMove{from: fileName(code), to: path.Join(staging, replacedDir, fileName(code))}
+
+		err := r.storage.Rename(m.from, m.to)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // the dictionary has not been saved yet
+		} else if err != nil {
+			return result, err
+		}
+
+		result = append(result, m)
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(b.dictionaries)) {
+		m := restoreMove{from: path.Join(staging, fileName(code)), to: fileName(code)}
+		if err := r.storage.Rename(m.from, m.to); err != nil {
+			return result, fmt.Errorf("dictionary %q: %w", code, err)
+		}
+
+		result = append(result, m)
+	}
+
+	return result, r.doSaveMetadata()
+}
+
+// doRollbackRestore moves the renamed files back and reopens the operations logs of the loaded dictionaries
+func (r *Registry) doRollbackRestore(moved []restoreMove) {
+	for i := len(moved) - 1; i >= 0; i-- {
+		if err := r.storage.Rename(moved[i].to, moved[i].from); err != nil {
+			r.log.Error("registry: restore rollback error", "file", moved[i].from, "moved", moved[i].to, "error", err)
+		}
+	}
+
+	for code := range r.items {
+		r.doOpenLog(code)
+	}
+}
+
+// doRestored replaces the dictionary in memory with the one which file is restored
+func (r *Registry) doRestored(code string, d restoredDictionary) error {
+	r.doForgetFailure(code)
+	r.setChecksum(code, d.info.Checksum)
+
+	// the log contains the changes of the replaced dictionary
+	if err := r.doRemoveLog(code); err != nil {
+		return err
+	}
+
+	if r.lazy {
+		delete(r.items, code)
+		r.unloaded[code] = unloadedItem{options: d.options}
+		r.cache.remove(code, false)
+
+		return nil
+	}
+
+	delete(r.unloaded, code)
+	r.items[code] = d.item
+	r.cache.add(code, d.info.DecodedSize, d.item.revision())
+	r.doOpenLog(code)
+	r.doMigrate(code, d.info)
+
+	return nil
+}
+
+// writeFile copies the local file to the storage
+func (r *Registry) writeFile(name string, filePath string) error {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return r.storage.Write(name, func(w storage.Writer) error {
+		_, err := io.Copy(w, src)
+
+		return err
+	})
+}
+
+// removeStaged removes the staged files left after a failed restore
+func (r *Registry) removeStaged(staging string, codes []string) {
+	for _, code := range codes {
+		if err := r.storage.Delete(path.Join(staging, fileName(code))); err != nil {
+			r.log.Error("registry: restore cleanup error", "file", path.Join(staging, fileName(code)), "error", err)
+		}
+	}
+}
+
+// readBackup unpacks the archive (gzipped or not) to the directory and validates its files
+func (r *Registry) readBackup(rd io.Reader, dir string) (backup, error) {
+	br := bufio.NewReader(rd)
+
+	var archive io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return backup{}, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+		defer gr.Close()
+
+		archive = gr
+	}
+
+	result := backup{
+		dictionaries: make(map[string]restoredDictionary),
+		metadata:     newMetadata(),
+	}
+
+	tr := tar.NewReader(&limitReader{r: archive, n: r.restoreLimit})
+	seen := make(map[string]struct{})
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return backup{}, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if header.Typeflag != tar.TypeReg {
+			return backup{}, fmt.Errorf("%w: %q is not a regular file", ErrInvalidBackup, header.Name)
+		}
+
+		if _, ok := seen[name]; ok {
+			return backup{}, fmt.Errorf("%w: duplicate file %q", ErrInvalidBackup, name)
+		}
+		seen[name] = struct{}{}
+
+		if name == storage.MetadataFile {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return backup{}, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+			}
+
+			result.metadata, err = decodeMetadata(data)
+			if err != nil {
+				return backup{}, fmt.Errorf("%w: metadata: %w", ErrInvalidBackup, err)
+			}
+
+			continue
+		}
+
+		code, ok := strings.CutSuffix(name, extension)
+		if !ok || !validFileCode(code) {
+			return backup{}, fmt.Errorf("%w: unexpected file %q", ErrInvalidBackup, header.Name)
+		}
+
+		d, err := r.readBackupFile(tr, path.Join(dir, fileName(code)))
+		if err != nil {
+			return backup{}, fmt.Errorf("%w: dictionary %q: %w", ErrInvalidBackup, code, err)
+		}
+
+		result.dictionaries[code] = d
+	}
+
+	result.metadata.rebuildInverted()
+
+	return result, nil
+}
+
+// readBackupFile writes the dictionary file to the path and checks that the dictionary can be loaded from it
+func (r *Registry) readBackupFile(rd io.Reader, filePath string) (restoredDictionary, error) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return restoredDictionary{}, err
+	}
+	defer f.Close()
+
+	item, info, err := loadItem(io.TeeReader(rd, f))
+	if err != nil {
+		return restoredDictionary{}, err
+	}
+
+	// the rest of the file is kept as is, the trailing data is reported by loadItem for the binary format
+	if _, err := io.Copy(f, rd); err != nil {
+		return restoredDictionary{}, err
+	}
+
+	result := restoredDictionary{path: filePath, options: item.Options, info: info}
+	if !r.lazy {
+		result.item = item
+	}
+
+	return result, nil
+}
+
+// limitReader fails with ErrBackupTooLarge if the reader has more than n bytes
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+
+	if l.n < 0 {
+		return 0, ErrBackupTooLarge
+	}
+
+	return n, err
+}
+
+// rebuildInverted builds the inverted aliases from the aliases, so they are consistent whatever the file contains
+func (m *Metadata) rebuildInverted() {
+	m.InvertedAliases = make(map[string][]string)
+
+	for alias := range m.Splits {
+		if _, ok := m.Aliases[alias]; !ok {
+			delete(m.Splits, alias)
+		}
+	}
+
+	for _, alias := range slices.Sorted(maps.Keys(m.Aliases)) {
+		for _, t := range m.targets(alias) {
+			m.InvertedAliases[t.Dictionary] = append(m.InvertedAliases[t.Dictionary], alias)
+		}
+	}
+}
+
+// checkAliases checks that the targets of the aliases exist and the alias chains are not cyclic
+func checkAliases(m Metadata, exists func(code string) bool) error {
+	for _, alias := range slices.Sorted(maps.Keys(m.Aliases)) {
+		if exists(alias) {
+			return fmt.Errorf("%w: alias %q has the name of a dictionary", ErrInvalidBackup, alias)
+		}
+
+		for _, t := range m.targets(alias) {
+			if _, ok := m.Aliases[t.Dictionary]; !ok && !exists(t.Dictionary) {
+				return fmt.Errorf("%w: alias %q points to a missing dictionary %q", ErrInvalidBackup, alias, t.Dictionary)
+			}
+		}
+
+		if reaches(m, alias, alias, exists, 0) {
+			return fmt.Errorf("%w: alias %q: %w", ErrInvalidBackup, alias, ErrAliasCycle)
+		}
+	}
+
+	return nil
+}
+
+// reaches checks if the alias chain starting from the targets of name reaches the alias or is too long
+func reaches(m Metadata, name string, alias string, exists func(code string) bool, depth int) bool {
+	if depth > 0 && name == alias || depth > maxAliasDepth {
+		return true
+	}
+
+	if exists(name) {
+		return false
+	}
+
+	for _, t := range m.targets(name) {
+		if reaches(m, t.Dictionary, alias, exists, depth+1) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,269 @@
+package spellchecker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_BackupRestore(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T, codes ...string) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		for _, code := range codes {
+			_, err := r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+			require.NoError(t, err)
+
+			item, err := r.GetItem(code)
+			require.NoError(t, err)
+			item.AddWeight(1, "hello"+code)
+		}
+
+		return r
+	}
+
+	source := newRegistry(t, "en", "de")
+	require.NoError(t, source.SetAlias("prod", "en"))
+
+	for _, compress := range []bool{true, false} {
+		t.Run(fmt.Sprintf("compress %v", compress), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, source.Backup(&buf, compress))
+
+			t.Run("replace", func(t *testing.T) {
+				t.Parallel()
+
+				r := newRegistry(t, "fr")
+
+				result, err := r.Restore(bytes.NewReader(buf.Bytes()), RestoreReplace)
+				require.NoError(t, err)
+				require.Equal(t, RestoreResult{Restored: []string{"de", "en"}, Removed: []string{"fr"}, Aliases: 1}, result)
+
+				item, err := r.GetItem("prod")
+				require.NoError(t, err)
+				require.True(t, item.Spellchecker.IsCorrect("helloen"))
+
+				_, err = r.GetItem("fr")
+				require.ErrorIs(t, err, ErrNotFound)
+
+				// the restored registry is saved
+				loaded := reopen(t, r)
+				require.Len(t, loaded.items, 2)
+				require.Equal(t, "en", loaded.metadata.Aliases["prod"])
+			})
+
+			t.Run("merge", func(t *testing.T) {
+				t.Parallel()
+
+				r := newRegistry(t, "fr", "en")
+				require.NoError(t, r.SetAlias("prod-fr", "fr"))
+
+				result, err := r.Restore(bytes.NewReader(buf.Bytes()), RestoreMerge)
+				require.NoError(t, err)
+				require.Equal(t, []string{"de", "en"}, result.Restored)
+				require.Empty(t, result.Removed)
+				require.Equal(t, 2, result.Aliases)
+
+				item, err := r.GetItem("en")
+				require.NoError(t, err)
+				require.True(t, item.Spellchecker.IsCorrect("helloen"))
+
+				_, err = r.GetItem("prod-fr")
+				require.NoError(t, err)
+			})
+		})
+	}
+
+	t.Run("lazy loading", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, source.Backup(&buf, true))
+
+		r, err := NewRegistry(context.Background(), t.TempDir(), WithLazyLoading(0))
+		require.NoError(t, err)
+
+		_, err = r.Restore(&buf, RestoreReplace)
+		require.NoError(t, err)
+		require.Empty(t, r.items)
+
+		item, err := r.GetItem("de")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("hellode"))
+	})
+}
+
+func Test_Registry_Restore_Invalid(t *testing.T) {
+	t.Parallel()
+
+	archive := func(t *testing.T, files map[string][]byte) []byte {
+		var buf bytes.Buffer
+
+		tw := tar.NewWriter(&buf)
+		for name, data := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: int64(len(data)), Mode: 0644}))
+			_, err := tw.Write(data)
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name  string
+		files map[string][]byte
+	}{
+		{name: "unexpected file", files: map[string][]byte{"../en.dict": []byte("x")}},
+		{name: "corrupted dictionary", files: map[string][]byte{"en.dict": []byte(`{"options":{}}`)}},
+		{name: "invalid metadata", files: map[string][]byte{"metadata": []byte("qwe")}},
+		{name: "alias to missing dictionary", files: map[string][]byte{"metadata": []byte(`{"aliases":{"prod":"xx"}}`)}},
+		{name: "alias cycle", files: map[string][]byte{"metadata": []byte(`{"aliases":{"a":"b","b":"a"}}`)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewRegistry(context.Background(), t.TempDir())
+			require.NoError(t, err)
+
+			_, err = r.Add("en", Options{Alphabet: "abc"})
+			require.NoError(t, err)
+
+			_, err = r.Restore(bytes.NewReader(archive(t, tt.files)), RestoreReplace)
+			require.ErrorIs(t, err, ErrInvalidBackup)
+			require.Contains(t, r.items, "en")
+			require.Empty(t, r.metadata.Aliases)
+		})
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir(), WithRestoreLimit(1024))
+		require.NoError(t, err)
+
+		data := archive(t, map[string][]byte{"metadata": bytes.Repeat([]byte(" "), 2048)})
+
+		_, err = r.Restore(bytes.NewReader(data), RestoreMerge)
+		require.ErrorIs(t, err, ErrInvalidBackup)
+		require.ErrorIs(t, err, ErrBackupTooLarge)
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Restore(bytes.NewReader([]byte("qweqweqwe")), RestoreMerge)
+		require.ErrorIs(t, err, ErrInvalidBackup)
+	})
+}
+
+func Test_Registry_Restore_Rollback(t *testing.T) {
+	t.Parallel()
+
+	source, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	for _, code := range []string{"en", "de"} {
+		_, err := source.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+		require.NoError(t, err)
+	}
+
+	item, err := source.GetItem("en")
+	require.NoError(t, err)
+	item.AddWeight(1, "hellonew")
+	item.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, source.Backup(&buf, true))
+
+	dir := t.TempDir()
+	s := &failingRenameStorage{Storage: storage.NewLocal(dir), fail: fileName("en")}
+
+	r, err := NewRegistry(context.Background(), "", WithStorage(s))
+	require.NoError(t, err)
+
+	for _, code := range []string{"en", "fr"} {
+		_, err := r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+		require.NoError(t, err)
+		require.NoError(t, r.Save(code))
+	}
+
+	require.NoError(t, r.SetAlias("prod", "fr"))
+
+	// the restored file of en fails to be renamed, de is renamed already
+	_, err = r.Restore(bytes.NewReader(buf.Bytes()), RestoreReplace)
+	require.Error(t, err)
+
+	require.Contains(t, r.items, "en")
+	require.Contains(t, r.items, "fr")
+	require.NotContains(t, r.items, "de")
+	require.Equal(t, map[string]string{"prod": "fr"}, r.metadata.Aliases)
+
+	loaded := reopen(t, r)
+	require.Contains(t, loaded.items, "en")
+	require.Contains(t, loaded.items, "fr")
+	require.NotContains(t, loaded.items, "de")
+	require.False(t, loaded.items["en"].Spellchecker.IsCorrect("hellonew"))
+	require.Empty(t, restoreFiles(t, dir))
+
+	result, err := r.Restore(bytes.NewReader(buf.Bytes()), RestoreReplace)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fr"}, result.Removed)
+
+	loaded = reopen(t, r)
+	require.Contains(t, loaded.items, "de")
+	require.NotContains(t, loaded.items, "fr")
+	require.True(t, loaded.items["en"].Spellchecker.IsCorrect("hellonew"))
+	require.Empty(t, restoreFiles(t, dir))
+}
+
+// failingRenameStorage fails to rename a file to the name once
+type failingRenameStorage struct {
+	storage.Storage
+	fail string
+}
+
+func (s *failingRenameStorage) Rename(from string, to string) error {
+	if to == s.fail {
+		s.fail = ""
+		return errors.New("rename failed")
+	}
+
+	return s.Storage.Rename(from, to)
+}
+
+// restoreFiles returns the files left in the restore directory
+func restoreFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var result []string
+
+	err := filepath.WalkDir(filepath.Join(dir, restoreDir), func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			result = append(result, p)
+		}
+
+		return err
+	})
+	require.NoError(t, err)
+
+	return result
+}
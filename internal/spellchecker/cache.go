@@ -0,0 +1,170 @@
+package spellchecker
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LoadStats contains counters of loading and unloading dictionaries
+type LoadStats struct {
+	Registered int   // all the dictionaries, loaded or not
+	Loaded     int   // dictionaries in memory
+	Size       int64 // estimated size of the loaded dictionaries in bytes
+	Budget     int64 // 0 - unlimited
+
+	Loads      uint64
+	LoadErrors uint64
+	Evictions  uint64
+	LoadTime   time.Duration // total time spent on loading
+}
+
+// cache tracks the loaded dictionaries in the order of use
+type cache struct {
+	mu sync.Mutex
+
+	order   *list.List // codes of the loaded dictionaries, the most recently used first
+	entries map[string]*cacheEntry
+	size    int64
+	stats   LoadStats
+}
+
+type cacheEntry struct {
+	elem     *list.Element
+	size     int64  // estimated by the size of the dictionary file
+	revision uint64 // revision of the dictionary when it was loaded or saved
+}
+
+func newCache() *cache {
+	return &cache{
+		order:   list.New(),
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// add registers the loaded dictionary as the most recently used one
+func (c *cache) add(code string, size int64, revision uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[code]; ok {
+		c.size -= e.size
+		c.order.Remove(e.elem)
+	}
+
+	c.entries[code] = &cacheEntry{
+		elem:     c.order.PushFront(code),
+		size:     size,
+		revision: revision,
+	}
+	c.size += size
+}
+
+func (c *cache) loaded(took time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Loads++
+	c.stats.LoadTime += took
+}
+
+func (c *cache) failed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.LoadErrors++
+}
+
+// touch marks the dictionary as the most recently used one
+func (c *cache) touch(code string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[code]; ok {
+		c.order.MoveToFront(e.elem)
+	}
+}
+
+// saved updates the size and the revision of the dictionary written to disk
+func (c *cache) saved(code string, size int64, revision uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[code]
+	if !ok {
+		return
+	}
+
+	c.size += size - e.size
+	e.size = size
+	e.revision = revision
+}
+
+// dirty checks if the dictionary has been changed since it was loaded or saved
+func (c *cache) dirty(code string, revision uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[code]
+
+	return !ok || e.revision != revision
+}
+
+func (c *cache) remove(code string, evicted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[code]
+	if !ok {
+		return
+	}
+
+	c.order.Remove(e.elem)
+	delete(c.entries, code)
+	c.size -= e.size
+
+	if evicted {
+		c.stats.Evictions++
+	}
+}
+
+// over checks if the loaded dictionaries exceed the budget
+func (c *cache) over(budget int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size > budget
+}
+
+// victims returns the least recently used dictionaries to be unloaded to fit into the budget.
+// The dictionaries to be kept are skipped.
+func (c *cache) victims(budget int64, kept func(code string) bool) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []string
+
+	size := c.size
+	for e := c.order.Back(); e != nil && size > budget; e = e.Prev() {
+		code := e.Value.(string)
+		if kept(code) {
+			continue
+		}
+
+		result = append(result, code)
+		size -= c.entries[code].size
+	}
+
+	return result
+}
+
+func (c *cache) snapshot() LoadStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := c.stats
+	result.Loaded = len(c.entries)
+	result.Size = c.size
+
+	return result
+}
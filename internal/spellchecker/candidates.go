@@ -0,0 +1,234 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	maxCandidateContexts = 3
+	maxCandidates        = 10000 // the rarest candidate is evicted to collect a new one
+)
+
+var (
+	ErrCandidatesDisabled = fmt.Errorf("candidates collection is disabled for the dictionary")
+	ErrCandidateNotFound  = fmt.Errorf("candidate not found")
+)
+
+type CandidatesOptions struct {
+	AutoApprove uint `json:"autoApprove,omitempty"` // approve a candidate automatically when it is seen this many times, 0 - disabled
+}
+
+type Candidate struct {
+	Word      string    `json:"word"`
+	Count     uint      `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Contexts  []string  `json:"contexts"`
+}
+
+// Candidates collects unknown words which are likely to be added to the dictionary
+type Candidates struct {
+	mu sync.Mutex
+
+	items    map[string]*Candidate
+	rejected map[string]struct{}
+}
+
+func NewCandidates() *Candidates {
+	return &Candidates{
+		items:    make(map[string]*Candidate),
+		rejected: make(map[string]struct{}),
+	}
+}
+
+func (c *Candidates) collect(word string, context string, now time.Time, threshold uint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.rejected[word]; ok {
+		return false
+	}
+
+	v, ok := c.items[word]
+	if !ok {
+		if len(c.items) >= maxCandidates {
+			c.evict()
+		}
+
+		v = &Candidate{Word: word, FirstSeen: now}
+		c.items[word] = v
+	}
+
+	v.Count++
+	v.LastSeen = now
+
+	if context != "" && len(v.Contexts) < maxCandidateContexts {
+		v.Contexts = append(v.Contexts, context)
+	}
+
+	if threshold > 0 && v.Count >= threshold {
+		delete(c.items, word)
+		return true
+	}
+
+	return false
+}
+
+// evict removes the candidate seen the least number of times, the one seen earlier is removed among equal ones
+func (c *Candidates) evict() {
+	var rarest *Candidate
+	for _, v := range c.items {
+		if rarest == nil || v.Count < rarest.Count || (v.Count == rarest.Count && v.LastSeen.Before(rarest.LastSeen)) {
+			rarest = v
+		}
+	}
+
+	if rarest != nil {
+		delete(c.items, rarest.Word)
+	}
+}
+
+func (c *Candidates) list() []Candidate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]Candidate, 0, len(c.items))
+	for _, v := range c.items {
+		item := *v
+		item.Contexts = append([]string(nil), v.Contexts...)
+		result = append(result, item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+
+		return result[i].Word < result[j].Word
+	})
+
+	return result
+}
+
+func (c *Candidates) remove(word string, reject bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.items[word]; !ok {
+		return ErrCandidateNotFound
+	}
+
+	delete(c.items, word)
+
+	if reject {
+		c.rejected[word] = struct{}{}
+	}
+
+	return nil
+}
+
+// Collect records the unknown word as a candidate to be added to the dictionary.
+// If the word has been seen enough times, it is added to the dictionary automatically.
+// Returns true if the word was added. The counters alone do not make the dictionary changed,
+// they are saved along with the next change, so checking texts does not cause saves.
+func (r RegistryItem) Collect(word string, context string) bool {
+	if r.Candidates == nil || r.Options.Candidates == nil {
+		return false
+	}
+
+	if !r.Candidates.collect(word, context, time.Now(), r.Options.Candidates.AutoApprove) {
+		return false
+	}
+
+	r.AddWeight(1, word)
+
+	return true
+}
+
+func (r RegistryItem) ListCandidates() ([]Candidate, error) {
+	if r.Candidates == nil {
+		return nil, ErrCandidatesDisabled
+	}
+
+	return r.Candidates.list(), nil
+}
+
+// ApproveCandidate adds the candidate to the dictionary
+func (r RegistryItem) ApproveCandidate(word string, weight uint) error {
+	if r.Candidates == nil {
+		return ErrCandidatesDisabled
+	}
+
+	if err := r.Candidates.remove(word, false); err != nil {
+		return err
+	}
+
+	r.AddWeight(weight, word)
+
+	return nil
+}
+
+// RejectCandidate removes the candidate, the word will not be collected anymore
+func (r RegistryItem) RejectCandidate(word string) error {
+	if r.Candidates == nil {
+		return ErrCandidatesDisabled
+	}
+
+	if err := r.Candidates.remove(word, true); err != nil {
+		return err
+	}
+
+	r.changed()
+
+	return nil
+}
+
+type candidatesData struct {
+	Items    []Candidate `json:"items"`
+	Rejected []string    `json:"rejected"`
+}
+
+func (c *Candidates) MarshalJSON() ([]byte, error) {
+	data := candidatesData{
+		Items: c.list(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data.Rejected = make([]string, 0, len(c.rejected))
+	for w := range c.rejected {
+		data.Rejected = append(data.Rejected, w)
+	}
+
+	sort.Strings(data.Rejected)
+
+	return json.Marshal(data)
+}
+
+func (c *Candidates) UnmarshalJSON(data []byte) error {
+	var value candidatesData
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*Candidate, len(value.Items))
+	for i := range value.Items {
+		c.items[value.Items[i].Word] = &value.Items[i]
+	}
+
+	c.rejected = make(map[string]struct{}, len(value.Rejected))
+	for _, w := range value.Rejected {
+		c.rejected[w] = struct{}{}
+	}
+
+	return nil
+}
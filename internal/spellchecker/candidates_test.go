@@ -0,0 +1,149 @@
+package spellchecker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegistryItem_Candidates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		require.False(t, item.Collect("foo", ""))
+
+		_, err = item.ListCandidates()
+		require.ErrorIs(t, err, ErrCandidatesDisabled)
+		require.ErrorIs(t, item.ApproveCandidate("foo", 1), ErrCandidatesDisabled)
+		require.ErrorIs(t, item.RejectCandidate("foo"), ErrCandidatesDisabled)
+	})
+
+	t.Run("collect, approve and reject", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", Candidates: &CandidatesOptions{AutoApprove: 5}})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		for i := 0; i < 4; i++ {
+			require.False(t, item.Collect("foo", ""))
+			require.False(t, item.Collect("bar", "context"))
+		}
+
+		require.True(t, item.Collect("foo", ""))
+		require.True(t, item.Spellchecker.IsCorrect("foo"))
+
+		item.Collect("baz", "")
+
+		list, err := item.ListCandidates()
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "bar", list[0].Word)
+		require.Len(t, list[0].Contexts, maxCandidateContexts)
+
+		require.NoError(t, item.ApproveCandidate("bar", 2))
+		require.True(t, item.Spellchecker.IsCorrect("bar"))
+		require.ErrorIs(t, item.ApproveCandidate("bar", 2), ErrCandidateNotFound)
+
+		require.NoError(t, item.RejectCandidate("baz"))
+		require.False(t, item.Collect("baz", ""))
+
+		list, err = item.ListCandidates()
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+
+	t.Run("collect does not change the dictionary", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", Candidates: &CandidatesOptions{AutoApprove: 2}})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		revision := item.revision()
+		require.False(t, item.Collect("foo", ""))
+		require.Equal(t, revision, item.revision())
+
+		require.True(t, item.Collect("foo", ""))
+		require.Greater(t, item.revision(), revision)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCandidates()
+		now := time.Now()
+
+		c.collect("frequent", "", now, 0)
+		c.collect("frequent", "", now, 0)
+		for i := 0; i < maxCandidates; i++ {
+			c.collect(fmt.Sprintf("word%d", i), "", now.Add(time.Duration(i)), 0)
+		}
+
+		list := c.list()
+		require.Len(t, list, maxCandidates)
+		require.Equal(t, "frequent", list[0].Word)
+
+		// the earliest of the rarest candidates is evicted
+		require.NotContains(t, c.items, "word0")
+		require.Contains(t, c.items, "word1")
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", Candidates: &CandidatesOptions{}})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		item.Collect("foo", "foo bar")
+		item.Collect("baz", "")
+		require.NoError(t, item.RejectCandidate("baz"))
+
+		require.NoError(t, r.Save("code"))
+
+		r2, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+
+		list, err := item.ListCandidates()
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "foo", list[0].Word)
+		require.Equal(t, []string{"foo bar"}, list[0].Contexts)
+		require.False(t, item.Collect("baz", ""))
+	})
+}
@@ -0,0 +1,157 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+)
+
+// Feedback contains corrections learned from the users' feedback
+type Feedback struct {
+	mu sync.RWMutex
+
+	corrections map[string]string   // misspelling => preferred fix
+	rejected    map[string][]string // misspelling => rejected suggestions
+}
+
+func NewFeedback() *Feedback {
+	return &Feedback{
+		corrections: make(map[string]string),
+		rejected:    make(map[string][]string),
+	}
+}
+
+// Correction returns the learned correction for the word
+func (f *Feedback) Correction(word string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	v, ok := f.corrections[word]
+
+	return v, ok
+}
+
+// IsRejected checks if the suggestion was rejected for the word
+func (f *Feedback) IsRejected(word string, suggestion string) bool {
+	if f == nil {
+		return false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return slices.Contains(f.rejected[word], suggestion)
+}
+
+func (f *Feedback) accept(word string, suggestion string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.corrections[word] = suggestion
+	f.doUnreject(word, suggestion)
+}
+
+func (f *Feedback) reject(word string, suggestion string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.corrections[word] == suggestion {
+		delete(f.corrections, word)
+	}
+
+	if !slices.Contains(f.rejected[word], suggestion) {
+		f.rejected[word] = append(f.rejected[word], suggestion)
+	}
+}
+
+func (f *Feedback) forget(word string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.corrections, word)
+	delete(f.rejected, word)
+}
+
+func (f *Feedback) doUnreject(word string, suggestion string) {
+	i := slices.Index(f.rejected[word], suggestion)
+	if i < 0 {
+		return
+	}
+
+	f.rejected[word] = slices.Delete(f.rejected[word], i, i+1)
+	if len(f.rejected[word]) == 0 {
+		delete(f.rejected, word)
+	}
+}
+
+// Accept records that the suggestion was accepted by a user as a fix for the word:
+// the suggestion weight is increased and the suggestion becomes the preferred fix for the word
+func (r RegistryItem) Accept(word string, suggestion string, weight uint) {
+	r.AddWeight(weight, suggestion)
+
+	if r.Feedback != nil {
+		r.Feedback.accept(word, suggestion)
+		r.changed()
+	}
+}
+
+// Reject records that the suggestion was rejected by a user as a fix for the word.
+// The suggestion is no longer returned for the word.
+func (r RegistryItem) Reject(word string, suggestion string) {
+	if r.Feedback != nil {
+		r.Feedback.reject(word, suggestion)
+		r.changed()
+	}
+}
+
+// Ignore records that the word was marked as correct by a user, so it is added to the dictionary
+func (r RegistryItem) Ignore(word string, weight uint) {
+	r.AddWeight(weight, word)
+
+	if r.Feedback != nil {
+		r.Feedback.forget(word)
+		r.changed()
+	}
+}
+
+type feedbackData struct {
+	Corrections map[string]string   `json:"corrections"`
+	Rejected    map[string][]string `json:"rejected"`
+}
+
+func (f *Feedback) MarshalJSON() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return json.Marshal(feedbackData{
+		Corrections: f.corrections,
+		Rejected:    f.rejected,
+	})
+}
+
+func (f *Feedback) UnmarshalJSON(data []byte) error {
+	var value feedbackData
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.corrections = value.Corrections
+	if f.corrections == nil {
+		f.corrections = make(map[string]string)
+	}
+
+	f.rejected = value.Rejected
+	if f.rejected == nil {
+		f.rejected = make(map[string][]string)
+	}
+
+	return nil
+}
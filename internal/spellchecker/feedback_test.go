@@ -0,0 +1,86 @@
+package spellchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegistryItem_Feedback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accept, reject and ignore", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", MaxErrors: 2})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		item.Accept("waapon", "weapon", 1)
+		require.True(t, item.Spellchecker.IsCorrect("weapon"))
+
+		v, ok := item.Feedback.Correction("waapon")
+		require.True(t, ok)
+		require.Equal(t, "weapon", v)
+
+		item.Reject("waapon", "weapon")
+		_, ok = item.Feedback.Correction("waapon")
+		require.False(t, ok)
+		require.True(t, item.Feedback.IsRejected("waapon", "weapon"))
+
+		item.Accept("waapon", "weapon", 1)
+		require.False(t, item.Feedback.IsRejected("waapon", "weapon"))
+
+		item.Ignore("waapon", 1)
+		require.True(t, item.Spellchecker.IsCorrect("waapon"))
+		_, ok = item.Feedback.Correction("waapon")
+		require.False(t, ok)
+	})
+
+	t.Run("nil feedback", func(t *testing.T) {
+		t.Parallel()
+
+		var f *Feedback
+
+		_, ok := f.Correction("waapon")
+		require.False(t, ok)
+		require.False(t, f.IsRejected("waapon", "weapon"))
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", MaxErrors: 2})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		item.Accept("waapon", "weapon", 1)
+		item.Reject("batle", "bottle")
+
+		require.NoError(t, r.Save("code"))
+
+		r2, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+
+		v, ok := item.Feedback.Correction("waapon")
+		require.True(t, ok)
+		require.Equal(t, "weapon", v)
+		require.True(t, item.Feedback.IsRejected("batle", "bottle"))
+	})
+}
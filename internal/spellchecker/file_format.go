@@ -0,0 +1,500 @@
+package spellchecker
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+
+	"github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/storage"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Dictionary and metadata files start with a header:
+//
+//	magic (4 bytes) | format version (uint16) | flags (uint16) | content length (uint64) | CRC-32C of the content (uint32)
+//
+// Metadata (and dictionaries saved by older versions) are JSON. Dictionaries are saved in the binary format,
+// its content is compressed according to the flags:
+//
+//	section length (uint32) | section (JSON with the options, feedback, rules, words etc.) | spellchecker data
+//
+// The spellchecker data is written only for the dictionaries without the words, otherwise the spellchecker is rebuilt from them.
+// Files written before the header was introduced are read as is without verification.
+const (
+	fileMagic  = "SCWD"
+	headerSize = 20
+
+	formatJSON   uint16 = 1
+	formatBinary uint16 = 2
+
+	// maxSectionSize protects from allocating memory for the length read from a corrupted file
+	maxSectionSize = 1 << 30
+)
+
+var (
+	ErrCorrupted         = errors.New("file is corrupted")
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Compression of the dictionary files
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// compressionFlags are stored in the header of the file
+var compressionFlags = map[Compression]uint16{
+	CompressionNone: 0,
+	CompressionGzip: 1,
+	CompressionZstd: 2,
+}
+
+func ParseCompression(value string) (Compression, error) {
+	result := Compression(value)
+	if _, ok := compressionFlags[result]; !ok {
+		return "", fmt.Errorf("unknown compression %q", value)
+	}
+
+	return result, nil
+}
+
+func compressionByFlags(flags uint16) (Compression, error) {
+	for c, f := range compressionFlags {
+		if f == flags {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: compression flags %d", ErrUnsupportedFormat, flags)
+}
+
+// WithCompression sets the compression of the saved dictionaries. Files are read regardless of their compression.
+func WithCompression(c Compression) RegistryOption {
+	return func(r *Registry) {
+		r.compression = c
+	}
+}
+
+// FileInfo describes the dictionary file on disk
+type FileInfo struct {
+	Version     uint16 // 0 - the file has no header
+	Compression Compression
+	Size        int64 // size of the content on disk
+	DecodedSize int64 // size of the uncompressed content
+	Checksum    uint32
+}
+
+type fileHeader struct {
+	version  uint16
+	flags    uint16
+	size     uint64
+	checksum uint32
+}
+
+func (h fileHeader) bytes() []byte {
+	result := make([]byte, headerSize)
+
+	copy(result, fileMagic)
+	binary.LittleEndian.PutUint16(result[4:], h.version)
+	binary.LittleEndian.PutUint16(result[6:], h.flags)
+	binary.LittleEndian.PutUint64(result[8:], h.size)
+	binary.LittleEndian.PutUint32(result[16:], h.checksum)
+
+	return result
+}
+
+func parseHeader(data []byte) fileHeader {
+	return fileHeader{
+		version:  binary.LittleEndian.Uint16(data[4:]),
+		flags:    binary.LittleEndian.Uint16(data[6:]),
+		size:     binary.LittleEndian.Uint64(data[8:]),
+		checksum: binary.LittleEndian.Uint32(data[16:]),
+	}
+}
+
+// readHeader reads the header of the file. false is returned for a legacy file without the header, nothing is read then.
+func readHeader(br *bufio.Reader) (fileHeader, bool, error) {
+	prefix, err := br.Peek(len(fileMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fileHeader{}, false, err
+	}
+
+	if string(prefix) != fileMagic {
+		return fileHeader{}, false, nil
+	}
+
+	data, err := br.Peek(headerSize)
+	if errors.Is(err, io.EOF) {
+		return fileHeader{}, false, fmt.Errorf("%w: truncated header", ErrCorrupted)
+	} else if err != nil {
+		return fileHeader{}, false, err
+	}
+
+	header := parseHeader(data)
+	_, err = br.Discard(headerSize)
+
+	return header, true, err
+}
+
+func verifyContent(header fileHeader, size uint64, checksum uint32) error {
+	if size != header.size {
+		return fmt.Errorf("%w: content length %d, expected %d", ErrCorrupted, size, header.size)
+	}
+
+	if checksum != header.checksum {
+		return fmt.Errorf("%w: checksum %08x, expected %08x", ErrCorrupted, checksum, header.checksum)
+	}
+
+	return nil
+}
+
+// encodeFile adds the header to the JSON content
+func encodeFile(content []byte) []byte {
+	header := fileHeader{
+		version:  formatJSON,
+		size:     uint64(len(content)),
+		checksum: crc32.Checksum(content, crcTable),
+	}
+
+	return append(header.bytes(), content...)
+}
+
+// decodeFile verifies the header of the JSON file and returns its content
+func decodeFile(data []byte) ([]byte, FileInfo, error) {
+	if !bytes.HasPrefix(data, []byte(fileMagic)) {
+		return data, jsonFileInfo(0, data), nil
+	}
+
+	if len(data) < headerSize {
+		return nil, FileInfo{}, fmt.Errorf("%w: truncated header", ErrCorrupted)
+	}
+
+	header := parseHeader(data)
+	if header.version != formatJSON {
+		return nil, FileInfo{Version: header.version}, fmt.Errorf("%w: version %d", ErrUnsupportedFormat, header.version)
+	}
+
+	content := data[headerSize:]
+	if err := verifyContent(header, uint64(len(content)), crc32.Checksum(content, crcTable)); err != nil {
+		return nil, FileInfo{Version: header.version}, err
+	}
+
+	return content, jsonFileInfo(header.version, content), nil
+}
+
+func jsonFileInfo(version uint16, content []byte) FileInfo {
+	return FileInfo{
+		Version:     version,
+		Compression: CompressionNone,
+		Size:        int64(len(content)),
+		DecodedSize: int64(len(content)),
+		Checksum:    crc32.Checksum(content, crcTable),
+	}
+}
+
+// writeItem streams the dictionary into the file in the binary format.
+// The header is written last, when the length and the checksum of the content are known.
+func writeItem(w storage.Writer, item RegistryItem, compression Compression) (FileInfo, error) {
+	flags, ok := compressionFlags[compression]
+	if !ok {
+		return FileInfo{}, fmt.Errorf("unknown compression %q", compression)
+	}
+
+	section, err := json.Marshal(item.section())
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(make([]byte, headerSize)); err != nil {
+		return FileInfo{}, err
+	}
+
+	crc := crc32.New(crcTable)
+	raw := &countWriter{w: io.MultiWriter(bw, crc)}
+
+	cw, err := compressWriter(raw, compression)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	decoded := &countWriter{w: cw}
+
+	if err := binary.Write(decoded, binary.LittleEndian, uint32(len(section))); err != nil {
+		return FileInfo{}, err
+	}
+
+	if _, err := decoded.Write(section); err != nil {
+		return FileInfo{}, err
+	}
+
+	if item.Words == nil {
+		if err := item.Spellchecker.Save(decoded); err != nil {
+			return FileInfo{}, err
+		}
+	}
+
+	if err := cw.Close(); err != nil {
+		return FileInfo{}, err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return FileInfo{}, err
+	}
+
+	header := fileHeader{version: formatBinary, flags: flags, size: uint64(raw.n), checksum: crc.Sum32()}
+	if _, err := w.WriteAt(header.bytes(), 0); err != nil {
+		return FileInfo{}, err
+	}
+
+	return FileInfo{
+		Version:     formatBinary,
+		Compression: compression,
+		Size:        raw.n,
+		DecodedSize: decoded.n,
+		Checksum:    header.checksum,
+	}, nil
+}
+
+// loadItem reads the dictionary from the file of any format version
+func loadItem(r io.Reader) (RegistryItem, FileInfo, error) {
+	br := bufio.NewReader(r)
+
+	header, ok, err := readHeader(br)
+	if err != nil {
+		return RegistryItem{}, FileInfo{}, err
+	}
+
+	if ok && header.version == formatBinary {
+		return readBinaryItem(br, header)
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return RegistryItem{}, FileInfo{}, err
+	}
+
+	if ok {
+		data = append(header.bytes(), data...)
+	}
+
+	content, info, err := decodeFile(data)
+	if err != nil {
+		return RegistryItem{}, info, err
+	}
+
+	var item RegistryItem
+	if err := json.Unmarshal(content, &item); err != nil {
+		return RegistryItem{}, info, err
+	}
+
+	return item, info, nil
+}
+
+// readBinaryItem decodes the dictionary while reading the file. The checksum is verified once the content is read,
+// a checksum mismatch is reported instead of the decoding error, as it is the cause of the latter.
+func readBinaryItem(br *bufio.Reader, header fileHeader) (RegistryItem, FileInfo, error) {
+	compression, err := compressionByFlags(header.flags)
+	if err != nil {
+		return RegistryItem{}, FileInfo{Version: header.version}, err
+	}
+
+	crc := crc32.New(crcTable)
+	raw := &countReader{r: io.TeeReader(io.LimitReader(br, int64(header.size)), crc)}
+
+	item, decoded, decodeErr := decodeBinary(raw, compression)
+
+	if _, err := io.Copy(io.Discard, raw); err != nil {
+		return RegistryItem{}, FileInfo{}, err
+	}
+
+	size := uint64(raw.n)
+	if _, err := br.ReadByte(); err == nil {
+		size++ // trailing data after the content
+	}
+
+	info := FileInfo{
+		Version:     header.version,
+		Compression: compression,
+		Size:        raw.n,
+		DecodedSize: decoded,
+		Checksum:    crc.Sum32(),
+	}
+
+	if err := verifyContent(header, size, info.Checksum); err != nil {
+		return RegistryItem{}, info, err
+	}
+
+	if decodeErr != nil {
+		return RegistryItem{}, info, decodeErr
+	}
+
+	return item, info, nil
+}
+
+func decodeBinary(r io.Reader, compression Compression) (RegistryItem, int64, error) {
+	dr, err := decompressReader(r, compression)
+	if err != nil {
+		return RegistryItem{}, 0, err
+	}
+	defer dr.Close()
+
+	decoded := &countReader{r: dr}
+
+	s, err := readSection(decoded)
+	if err != nil {
+		return RegistryItem{}, 0, err
+	}
+
+	var sc *spellchecker.Spellchecker
+	if s.Words != nil {
+		sc, err = s.Words.spellchecker(s.Options)
+	} else {
+		sc, err = spellchecker.Load(decoded)
+	}
+
+	if err != nil {
+		return RegistryItem{}, 0, err
+	}
+
+	item, err := newItem(sc, s)
+	if err != nil {
+		return RegistryItem{}, 0, err
+	}
+
+	// reading till the end verifies the compressed stream
+	if _, err := io.Copy(io.Discard, decoded); err != nil {
+		return RegistryItem{}, 0, err
+	}
+
+	return item, decoded.n, nil
+}
+
+func readSection(r io.Reader) (section, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return section{}, err
+	}
+
+	if size > maxSectionSize {
+		return section{}, fmt.Errorf("%w: section length %d", ErrCorrupted, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return section{}, err
+	}
+
+	var result section
+	if err := json.Unmarshal(data, &result); err != nil {
+		return section{}, err
+	}
+
+	return result, nil
+}
+
+func compressWriter(w io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+func decompressReader(r io.Reader, compression Compression) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// doMigrate saves the dictionary loaded from a file of an older format in the binary format
+func (r *Registry) doMigrate(code string, info FileInfo) {
+	if info.Version == formatBinary || r.readOnly {
+		return
+	}
+
+	if err := r.doSave(code, false); err != nil {
+		r.log.Error("registry: dictionary migration error", "dictionary", code, "error", err)
+		return
+	}
+
+	r.log.Info("registry: dictionary migrated to the binary format", "dictionary", code, "version", info.Version)
+}
+
+// Verify checks the integrity of the dictionary file and that the dictionary can be decoded from it
+func (r *Registry) Verify(code string) (FileInfo, error) {
+	r.mu.RLock()
+	ok := r.doExists(code)
+	r.mu.RUnlock()
+
+	if !ok {
+		return FileInfo{}, ErrNotFound
+	}
+
+	_, info, err := r.readItem(fileName(code))
+
+	var pathErr *fs.PathError
+	if err == nil || errors.As(err, &pathErr) || errors.Is(err, ErrCorrupted) || errors.Is(err, ErrUnsupportedFormat) {
+		return info, err
+	}
+
+	return info, fmt.Errorf("%w: %w", ErrCorrupted, err)
+}
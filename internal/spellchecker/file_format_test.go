@@ -0,0 +1,232 @@
+package spellchecker
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeFile(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"options":{}}`)
+	encoded := encodeFile(content)
+
+	flipped := append([]byte(nil), encoded...)
+	flipped[len(flipped)-1] ^= 0xff
+
+	binaryVersion := append([]byte(nil), encoded...)
+	binaryVersion[4] = byte(formatBinary)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		wantContent []byte
+		wantVersion uint16
+		wantErr     error
+	}{
+		{name: "with header", data: encoded, wantContent: content, wantVersion: formatJSON},
+		{name: "legacy", data: content, wantContent: content},
+		{name: "truncated header", data: encoded[:10], wantErr: ErrCorrupted},
+		{name: "truncated content", data: encoded[:len(encoded)-1], wantErr: ErrCorrupted},
+		{name: "checksum mismatch", data: flipped, wantErr: ErrCorrupted},
+		{name: "binary version", data: binaryVersion, wantErr: ErrUnsupportedFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, info, err := decodeFile(tt.data)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantContent, result)
+			require.Equal(t, tt.wantVersion, info.Version)
+			require.EqualValues(t, len(content), info.Size)
+		})
+	}
+}
+
+func Test_Registry_Verify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	_, err = r.Add("en", Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	_, err = r.Verify("en")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, r.Save("en"))
+
+	info, err := r.Verify("en")
+	require.NoError(t, err)
+	require.Equal(t, formatBinary, info.Version)
+
+	data, err := os.ReadFile(fullPath(dir, "en"))
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(fullPath(dir, "en"), data, 0644))
+
+	_, err = r.Verify("en")
+	require.ErrorIs(t, err, ErrCorrupted)
+
+	_, err = r.Verify("xx")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	t.Run("corrupted file is quarantined on load", func(t *testing.T) {
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+		require.NotContains(t, r.items, "en")
+
+		failed := r.Failed()
+		require.Len(t, failed, 1)
+		require.True(t, failed[0].Quarantined)
+	})
+}
+
+func Test_Registry_Compression(t *testing.T) {
+	t.Parallel()
+
+	for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			r, err := NewRegistry(context.Background(), dir, WithCompression(compression))
+			require.NoError(t, err)
+
+			_, err = r.Add("en", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", Owner: "team"})
+			require.NoError(t, err)
+
+			item, err := r.GetItem("en")
+			require.NoError(t, err)
+			item.AddWeight(1, "hello", "world")
+			item.Rules.Forbid("helo")
+
+			require.NoError(t, r.Save("en"))
+
+			info, err := r.Verify("en")
+			require.NoError(t, err)
+			require.Equal(t, compression, info.Compression)
+
+			options, _, err := readOptions(openFile(t, fullPath(dir, "en")))
+			require.NoError(t, err)
+			require.Equal(t, "team", options.Owner)
+
+			// files are read regardless of the configured compression
+			r, err = NewRegistry(context.Background(), dir, WithCompression(CompressionNone))
+			require.NoError(t, err)
+
+			item, err = r.GetItem("en")
+			require.NoError(t, err)
+			require.True(t, item.Spellchecker.IsCorrect("hello"))
+			require.True(t, item.Rules.IsForbidden("helo"))
+			require.Equal(t, "team", item.Options.Owner)
+		})
+	}
+}
+
+func Test_Registry_WordsSavedOnce(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	_, err = r.Add("en", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", MaxErrors: 1})
+	require.NoError(t, err)
+
+	item, err := r.GetItem("en")
+	require.NoError(t, err)
+	item.AddWeight(1, "hello", "hellp")
+	item.AddWeight(2, "hellp")
+	item.Release()
+
+	require.NoError(t, r.Save("en"))
+
+	// the spellchecker data does not follow the section
+	data, err := os.ReadFile(fullPath(dir, "en"))
+	require.NoError(t, err)
+	require.Len(t, data, headerSize+4+int(binary.LittleEndian.Uint32(data[headerSize:])))
+
+	r, err = NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	item, err = r.GetItem("en")
+	require.NoError(t, err)
+	defer item.Release()
+
+	require.Equal(t, map[string]uint{"hello": 1, "hellp": 3}, item.Words.Counts())
+	require.True(t, item.Spellchecker.IsCorrect("hello"))
+
+	suggestions := item.Spellchecker.SuggestScore("hellq", 2).Suggestions
+	require.Len(t, suggestions, 2)
+	require.Equal(t, "hellp", suggestions[0].Value)
+}
+
+func Test_Registry_MigrateLegacy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	_, err = r.Add("en", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+	require.NoError(t, err)
+
+	item, err := r.GetItem("en")
+	require.NoError(t, err)
+	item.AddWeight(1, "hello")
+
+	content, err := json.Marshal(&item)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		version uint16
+	}{
+		{name: "json without header", data: content, version: 0},
+		{name: "json with header", data: encodeFile(content), version: formatJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(fullPath(dir, "en"), tt.data, 0644))
+
+			_, info, err := loadItem(openFile(t, fullPath(dir, "en")))
+			require.NoError(t, err)
+			require.Equal(t, tt.version, info.Version)
+
+			r, err := NewRegistry(context.Background(), dir)
+			require.NoError(t, err)
+
+			item, err := r.GetItem("en")
+			require.NoError(t, err)
+			require.True(t, item.Spellchecker.IsCorrect("hello"))
+
+			_, info, err = loadItem(openFile(t, fullPath(dir, "en")))
+			require.NoError(t, err)
+			require.Equal(t, formatBinary, info.Version)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package spellchecker
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"time"
+)
+
+// languageTag is a simplified BCP 47 tag syntax: a primary language subtag followed by optional subtags (en, en-US, zh-Hant-TW)
+var languageTag = regexp.MustCompile(`^[a-zA-Z]{2,8}(-[a-zA-Z0-9]{1,8})*$`)
+
+// InfoUpdate contains descriptive fields of a dictionary to be changed. Nil fields are left as is.
+type InfoUpdate struct {
+	Language    *string
+	Description *string
+	Owner       *string
+	Labels      map[string]string // replaces all the labels of the dictionary
+}
+
+// Update changes descriptive fields of the dictionary
+func (r *Registry) Update(code string, update InfoUpdate) (Options, error) {
+	// the dictionary may be loaded to be updated
+	defer r.evictOver(code)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, err := r.doItem(code)
+	if err != nil {
+		return Options{}, err
+	}
+
+	if update.Language != nil {
+		if err := validateLanguage(*update.Language); err != nil {
+			return Options{}, err
+		}
+
+		item.Options.Language = *update.Language
+	}
+
+	if update.Description != nil {
+		item.Options.Description = *update.Description
+	}
+
+	if update.Owner != nil {
+		item.Options.Owner = *update.Owner
+	}
+
+	if update.Labels != nil {
+		item.Options.Labels = maps.Clone(update.Labels)
+	}
+
+	item.Options.UpdatedAt = time.Now().UTC()
+
+	r.items[code] = item
+	item.changed()
+
+	return item.Options, nil
+}
+
+func validateLanguage(tag string) error {
+	if tag != "" && !languageTag.MatchString(tag) {
+		return fmt.Errorf("%w: invalid language tag %q", ErrInvalidOptions, tag)
+	}
+
+	return nil
+}
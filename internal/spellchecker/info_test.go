@@ -0,0 +1,72 @@
+package spellchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_Update(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{
+			Alphabet:    "abc",
+			Language:    "en",
+			Description: "old",
+			Labels:      map[string]string{"team": "search"},
+		})
+		require.NoError(t, err)
+
+		return r
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		_, err := r.Update("qwerty", InfoUpdate{})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		_, err := r.Update("code", InfoUpdate{Language: ptr("en_US")})
+		require.ErrorIs(t, err, ErrInvalidOptions)
+		require.Equal(t, "en", r.items["code"].Options.Language)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+		created := r.items["code"].Options
+
+		result, err := r.Update("code", InfoUpdate{
+			Language: ptr("en-US"),
+			Owner:    ptr("search-team"),
+			Labels:   map[string]string{"env": "prod"},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "en-US", result.Language)
+		require.Equal(t, "old", result.Description)
+		require.Equal(t, "search-team", result.Owner)
+		require.Equal(t, map[string]string{"env": "prod"}, result.Labels)
+		require.Equal(t, created.CreatedAt, result.CreatedAt)
+		require.False(t, result.UpdatedAt.Before(created.UpdatedAt))
+		require.Equal(t, result, r.items["code"].Options)
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -4,23 +4,188 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/compound"
+	"github.com/f1monkey/spellchecker-web/internal/phonetic"
 )
 
 type RegistryItem struct {
 	Spellchecker *spellchecker.Spellchecker
+	Phonetic     *phonetic.Index
+	Feedback     *Feedback
+	Candidates   *Candidates
+	Rules        *Rules
+	Language     *Language
+	Words        *Words
 	Options      Options
+
+	id      uint64 // unique within the process, a dictionary loaded again gets a new one
+	changes *atomic.Uint64
+	log     *walLog       // operations log, nil if disabled
+	pins    *atomic.Int64 // requests using the dictionary
+	lease   *lease        // the pin of the copy returned to a request
 }
 
+// itemIDs generates the identifiers of the dictionaries
+var itemIDs atomic.Uint64
+
 type Options struct {
-	Alphabet  string `json:"alphabet"`
-	MaxErrors uint   `json:"maxErrors"`
+	Alphabet   string             `json:"alphabet"`
+	MaxErrors  uint               `json:"maxErrors"`
+	Phonetic   string             `json:"phonetic,omitempty"`
+	Compound   *compound.Options  `json:"compound,omitempty"`
+	MinScore   float64            `json:"minScore,omitempty"`
+	MinMargin  float64            `json:"minMargin,omitempty"`
+	Candidates *CandidatesOptions `json:"candidates,omitempty"`
+
+	Language    string            `json:"language,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt,omitzero"`
+	UpdatedAt   time.Time         `json:"updatedAt,omitzero"`
 }
 
+// section is the part of the dictionary file stored along with the spellchecker data
+type section struct {
+	Options    Options         `json:"options"`
+	Phonetic   *phonetic.Index `json:"phonetic,omitempty"`
+	Feedback   *Feedback       `json:"feedback,omitempty"`
+	Candidates *Candidates     `json:"candidates,omitempty"`
+	Rules      *Rules          `json:"rules,omitempty"`
+	Words      *Words          `json:"words,omitempty"`
+}
+
+// src is the legacy JSON format of the dictionary file
 type src struct {
-	Options      Options `json:"options"`
-	Spellchecker []byte  `json:"spellchecker"`
+	Options      Options         `json:"options"`
+	Spellchecker []byte          `json:"spellchecker"`
+	Phonetic     *phonetic.Index `json:"phonetic,omitempty"`
+	Feedback     *Feedback       `json:"feedback,omitempty"`
+	Candidates   *Candidates     `json:"candidates,omitempty"`
+	Rules        *Rules          `json:"rules,omitempty"`
+	Words        *Words          `json:"words,omitempty"`
+}
+
+// newItem creates the dictionary read from a file, the indexes missing in the file are initialized
+func newItem(sc *spellchecker.Spellchecker, s section) (RegistryItem, error) {
+	result := RegistryItem{
+		Spellchecker: sc,
+		Phonetic:     s.Phonetic,
+		Feedback:     s.Feedback,
+		Candidates:   s.Candidates,
+		Rules:        s.Rules,
+		Words:        s.Words,
+		Options:      s.Options,
+		Language:     NewLanguage(),
+		id:           itemIDs.Add(1),
+		changes:      new(atomic.Uint64),
+		pins:         new(atomic.Int64),
+	}
+
+	if result.Feedback == nil {
+		result.Feedback = NewFeedback()
+	}
+
+	if result.Rules == nil {
+		result.Rules = NewRules()
+	}
+
+	if result.Candidates == nil && result.Options.Candidates != nil {
+		result.Candidates = NewCandidates()
+	}
+
+	if result.Phonetic == nil && result.Options.Phonetic != "" {
+		var err error
+
+		result.Phonetic, err = phonetic.NewIndex(result.Options.Phonetic)
+		if err != nil {
+			return RegistryItem{}, err
+		}
+	}
+
+	return result, nil
+}
+
+func (r RegistryItem) section() section {
+	return section{
+		Options:    r.Options,
+		Phonetic:   r.Phonetic,
+		Feedback:   r.Feedback,
+		Candidates: r.Candidates,
+		Rules:      r.Rules,
+		Words:      r.Words,
+	}
+}
+
+// AddWeight adds words to the spellchecker and to all the additional indexes of the dictionary.
+// The words are written to the operations log first, if it is enabled.
+func (r RegistryItem) AddWeight(weight uint, words ...string) {
+	if r.log != nil {
+		// a save must not take the offset of the log between writing the record and applying it
+		r.log.mu.Lock()
+		defer r.log.mu.Unlock()
+
+		r.log.doAppend(walRecord{Weight: weight, Words: words})
+	}
+
+	if r.Language != nil {
+		r.Language.add(r.newWords(words)...)
+	}
+
+	r.Spellchecker.AddWeight(weight, words...)
+
+	if r.Words != nil {
+		r.Words.add(weight, words...)
+	}
+
+	if r.Phonetic != nil {
+		r.Phonetic.AddWeight(weight, words...)
+	}
+
+	r.changed()
+}
+
+// changed increments the revision of the dictionary
+func (r RegistryItem) changed() {
+	if r.changes != nil {
+		r.changes.Add(1)
+	}
+}
+
+// revision grows on every change of the dictionary, so the dictionary is not saved if it has not changed since the last save
+func (r RegistryItem) revision() uint64 {
+	var result uint64
+
+	if r.changes != nil {
+		result = r.changes.Load()
+	}
+
+	if r.Rules != nil {
+		result += r.Rules.revision()
+	}
+
+	return result
+}
+
+// newWords returns the words not known by the spellchecker yet
+func (r RegistryItem) newWords(words []string) []string {
+	result := make([]string, 0, len(words))
+	seen := make(map[string]struct{}, len(words))
+
+	for _, w := range words {
+		if _, ok := seen[w]; ok || r.Spellchecker.IsCorrect(w) {
+			continue
+		}
+
+		seen[w] = struct{}{}
+		result = append(result, w)
+	}
+
+	return result
 }
 
 func (r *RegistryItem) MarshalJSON() ([]byte, error) {
@@ -34,6 +199,11 @@ func (r *RegistryItem) MarshalJSON() ([]byte, error) {
 	return json.Marshal(src{
 		Options:      r.Options,
 		Spellchecker: buf.Bytes(),
+		Phonetic:     r.Phonetic,
+		Feedback:     r.Feedback,
+		Candidates:   r.Candidates,
+		Rules:        r.Rules,
+		Words:        r.Words,
 	})
 }
 
@@ -53,8 +223,19 @@ func (r *RegistryItem) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	r.Spellchecker = sc
-	r.Options = value.Options
+	item, err := newItem(sc, section{
+		Options:    value.Options,
+		Phonetic:   value.Phonetic,
+		Feedback:   value.Feedback,
+		Candidates: value.Candidates,
+		Rules:      value.Rules,
+		Words:      value.Words,
+	})
+	if err != nil {
+		return err
+	}
+
+	*r = item
 
 	return nil
 }
@@ -0,0 +1,96 @@
+package spellchecker
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/f1monkey/spellchecker-web/internal/langdetect"
+)
+
+// Language holds the n-gram profile used to detect the language of a text.
+// The profile is built from the dictionary words on first use and is not saved, it is kept in memory when the dictionary is unloaded.
+type Language struct {
+	mu      sync.Mutex
+	profile *langdetect.Profile
+}
+
+func NewLanguage() *Language {
+	return &Language{}
+}
+
+// add updates the profile if it is already built
+func (l *Language) add(words ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.profile != nil {
+		l.profile.Add(words...)
+	}
+}
+
+// built returns the profile if it is already built
+func (l *Language) built() *langdetect.Profile {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.profile
+}
+
+// Profile returns the language profile of the dictionary, an alias is resolved by the key as ResolveItem does.
+// The profiles of unloaded dictionaries are kept in memory, so only the dictionaries never used are loaded to build them.
+func (r *Registry) Profile(name string, key string) (string, *langdetect.Profile, error) {
+	r.mu.RLock()
+	code, _, err := r.doResolve(name, func(alias string, targets []AliasTarget) string {
+		return pickTarget(alias, targets, key)
+	})
+	profile := r.unloaded[code].profile
+	r.mu.RUnlock()
+
+	if errors.Is(err, errNotLoaded) && profile != nil {
+		return code, profile, nil
+	} else if err != nil && !errors.Is(err, errNotLoaded) {
+		return "", nil, err
+	}
+
+	item, err := r.item(code)
+	if err != nil {
+		return "", nil, err
+	}
+	defer item.Release()
+
+	return code, item.Profile(), nil
+}
+
+// Profile returns the n-gram profile of the dictionary
+func (r RegistryItem) Profile() *langdetect.Profile {
+	if r.Language == nil {
+		return buildProfile(r.Words)
+	}
+
+	r.Language.mu.Lock()
+	defer r.Language.mu.Unlock()
+
+	if r.Language.profile == nil {
+		r.Language.profile = buildProfile(r.Words)
+	}
+
+	return r.Language.profile
+}
+
+// buildProfile builds the profile from the words of the dictionary, it is empty if the words are not kept
+func buildProfile(words *Words) *langdetect.Profile {
+	result := langdetect.NewProfile()
+	if words == nil {
+		return result
+	}
+
+	for w := range words.Counts() {
+		result.Add(w)
+	}
+
+	return result
+}
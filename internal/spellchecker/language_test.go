@@ -0,0 +1,69 @@
+package spellchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/f1monkey/spellchecker-web/internal/langdetect"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegistryItem_Profile(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", MaxErrors: 2})
+	require.NoError(t, err)
+
+	item, err := r.GetItem("code")
+	require.NoError(t, err)
+
+	item.AddWeight(1, "hello", "world")
+
+	profile := item.Profile()
+
+	want := langdetect.NewProfile()
+	want.Add("hello", "world")
+	require.Equal(t, want.Len(), profile.Len())
+
+	// the profile is cached and updated with new words only
+	item.AddWeight(1, "hello", "quick", "quick")
+
+	cached := item.Profile()
+	require.Same(t, profile, cached)
+
+	want.Add("quick")
+	require.Equal(t, want.Len(), cached.Len())
+	require.Equal(t, want.Score(langdetect.Grams("quick")), cached.Score(langdetect.Grams("quick")))
+}
+
+func Test_RegistryItem_Words(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", MaxErrors: 2})
+	require.NoError(t, err)
+
+	item, err := r.GetItem("code")
+	require.NoError(t, err)
+	require.Empty(t, item.Words.Counts())
+
+	item.AddWeight(1, "hello", "world", "hello")
+	item.AddWeight(3, "world")
+	require.Equal(t, map[string]uint{"hello": 2, "world": 4}, item.Words.Counts())
+
+	require.NoError(t, r.Save("code"))
+
+	r2, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	item, err = r2.GetItem("code")
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint{"hello": 2, "world": 4}, item.Words.Counts())
+}
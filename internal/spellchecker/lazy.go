@@ -0,0 +1,366 @@
+package spellchecker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/langdetect"
+)
+
+// errNotLoaded is returned by doResolve for a dictionary which is registered but not loaded yet
+var errNotLoaded = errors.New("dictionary is not loaded")
+
+// WithLazyLoading registers the dictionaries found in the directory without loading them, a dictionary is loaded on first use.
+// If budget > 0, the least recently used dictionaries are unloaded when the size of the loaded ones exceeds the budget (in bytes).
+// The size of a dictionary in memory is estimated by the uncompressed size of its file. Changed dictionaries are saved before unloading.
+// The dictionaries returned by GetItem and ResolveItem are not unloaded until they are released.
+func WithLazyLoading(budget int64) RegistryOption {
+	return func(r *Registry) {
+		r.lazy = true
+		r.budget = budget
+	}
+}
+
+// Stats returns counters of loading and unloading dictionaries
+func (r *Registry) Stats() LoadStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := r.cache.snapshot()
+	result.Registered = len(r.items) + len(r.unloaded)
+	result.Budget = r.budget
+
+	return result
+}
+
+// unloadedItem is a registered dictionary which is not loaded.
+// The language profile built before unloading is kept, so detecting the language does not load the dictionary again.
+type unloadedItem struct {
+	options Options
+	profile *langdetect.Profile
+}
+
+// lease is a pin of the dictionary taken by a request, a pinned dictionary is not unloaded
+type lease struct {
+	pins     *atomic.Int64
+	released atomic.Bool
+}
+
+// Release unpins the dictionary returned by GetItem or ResolveItem, so it can be unloaded to fit into the memory budget.
+// The dictionary must not be used after that. Releasing it again does nothing.
+func (r RegistryItem) Release() {
+	if r.lease != nil && r.lease.released.CompareAndSwap(false, true) {
+		r.lease.pins.Add(-1)
+	}
+}
+
+func (r RegistryItem) pinned() bool {
+	return r.pins != nil && r.pins.Load() > 0
+}
+
+// doPin returns a copy of the dictionary pinned until it is released.
+// Must be called with the lock held, so the dictionary is not unloaded in the meantime.
+func (r *Registry) doPin(item RegistryItem) RegistryItem {
+	if !r.lazy || r.budget <= 0 || item.pins == nil {
+		return item
+	}
+
+	item.pins.Add(1)
+	item.lease = &lease{pins: item.pins}
+
+	return item
+}
+
+// item returns the dictionary by its code (aliases are not resolved) loading it if needed. The dictionary must be released.
+func (r *Registry) item(code string) (RegistryItem, error) {
+	r.mu.RLock()
+	item, ok := r.items[code]
+	_, unloaded := r.unloaded[code]
+	if ok {
+		item = r.doPin(item)
+	}
+	r.mu.RUnlock()
+
+	if ok {
+		r.cache.touch(code)
+		r.evictOver(code)
+
+		return item, nil
+	}
+
+	if !unloaded {
+		return RegistryItem{}, ErrNotFound
+	}
+
+	return r.load(code)
+}
+
+// load reads the registered dictionary from disk, the dictionary is returned pinned.
+// Dictionaries are loaded one at a time, so concurrent requests do not exceed the budget together.
+func (r *Registry) load(code string) (RegistryItem, error) {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.mu.RLock()
+	item, ok := r.items[code]
+	_, unloaded := r.unloaded[code]
+	if ok {
+		item = r.doPin(item)
+	}
+	r.mu.RUnlock()
+
+	if ok {
+		r.cache.touch(code)
+		return item, nil
+	}
+
+	if !unloaded {
+		return RegistryItem{}, ErrNotFound
+	}
+
+	start := time.Now()
+
+	item, info, err := r.doLoad(code)
+
+	item, err = r.insert(code, item, info, err, time.Since(start))
+	if err != nil {
+		return RegistryItem{}, err
+	}
+
+	r.evict(code)
+
+	return item, nil
+}
+
+// insert registers the dictionary loaded from disk and pins it
+func (r *Registry) insert(code string, item RegistryItem, info FileInfo, loadErr error, took time.Duration) (RegistryItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the dictionary may be loaded by a write operation or deleted in the meantime
+	if loaded, ok := r.items[code]; ok {
+		return r.doPin(loaded), nil
+	}
+
+	if _, ok := r.unloaded[code]; !ok {
+		return RegistryItem{}, ErrNotFound
+	}
+
+	if loadErr != nil {
+		return RegistryItem{}, r.doFail(code, loadErr)
+	}
+
+	r.doLoaded(code, item, info, took)
+
+	return r.doPin(r.items[code]), nil
+}
+
+// doItem is the same as item, but must be called with the write lock held. The dictionary is not pinned,
+// the loaded ones are unloaded on the next load if the budget is exceeded.
+func (r *Registry) doItem(code string) (RegistryItem, error) {
+	if item, ok := r.items[code]; ok {
+		r.cache.touch(code)
+		return item, nil
+	}
+
+	if _, ok := r.unloaded[code]; !ok {
+		return RegistryItem{}, ErrNotFound
+	}
+
+	start := time.Now()
+
+	item, info, err := r.doLoad(code)
+	if err != nil {
+		return RegistryItem{}, r.doFail(code, err)
+	}
+
+	r.doLoaded(code, item, info, time.Since(start))
+
+	return r.items[code], nil
+}
+
+func (r *Registry) doLoaded(code string, item RegistryItem, info FileInfo, took time.Duration) {
+	// the file is not changed since unloading, otherwise the entry would be replaced
+	if u, ok := r.unloaded[code]; ok && u.profile != nil && item.Language != nil {
+		item.Language.profile = u.profile
+	}
+
+	delete(r.unloaded, code)
+	r.items[code] = item
+	r.doForgetFailure(code)
+
+	r.cache.add(code, info.DecodedSize, item.revision())
+	r.cache.loaded(took)
+	r.setChecksum(code, info.Checksum)
+
+	r.log.Info("registry: loaded dictionary", "dictionary", code, "duration", took)
+
+	r.doOpenLog(code)
+	r.doMigrate(code, info)
+}
+
+// evictOver unloads the dictionaries if the loaded ones exceed the budget,
+// e.g. the pinned ones could not be unloaded when another dictionary was loaded
+func (r *Registry) evictOver(keep string) {
+	if !r.lazy || r.budget <= 0 || !r.cache.over(r.budget) {
+		return
+	}
+
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.evict(keep)
+}
+
+// evict unloads the least recently used dictionaries until the loaded ones fit into the budget.
+// Changed dictionaries are saved first without holding the lock, so lookups are not blocked.
+// The pinned dictionaries, the ones failed to save and the ones changed during saving are kept in memory.
+// Must be called with loadMu held, so the dictionaries are not replaced or deleted while they are saved.
+func (r *Registry) evict(keep string) {
+	if !r.lazy || r.budget <= 0 {
+		return
+	}
+
+	r.mu.RLock()
+	var victims []string
+	items := make(map[string]RegistryItem)
+	for _, code := range r.cache.victims(r.budget, func(code string) bool {
+		return code == keep || r.items[code].pinned()
+	}) {
+		victims = append(victims, code)
+		items[code] = r.items[code]
+	}
+	r.mu.RUnlock()
+
+	for _, code := range victims {
+		item := items[code]
+		if !r.cache.dirty(code, item.revision()) {
+			continue
+		}
+
+		if err := r.saveItem(code, item, false); err != nil {
+			r.log.Error("registry: dictionary save before unloading error", "dictionary", code, "error", err)
+			delete(items, code)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range victims {
+		saved, ok := items[code]
+		if !ok {
+			continue
+		}
+
+		// the dictionary may be pinned or changed while it was saved
+		item, ok := r.items[code]
+		if !ok || item.id != saved.id || item.pinned() || r.cache.dirty(code, item.revision()) {
+			continue
+		}
+
+		r.doCloseLog(code)
+		delete(r.items, code)
+		r.unloaded[code] = unloadedItem{options: item.Options, profile: item.Language.built()}
+		r.cache.remove(code, true)
+
+		r.log.Info("registry: unloaded dictionary", "dictionary", code)
+	}
+}
+
+// doExists checks if the dictionary is registered, loaded or not
+func (r *Registry) doExists(code string) bool {
+	if _, ok := r.items[code]; ok {
+		return true
+	}
+
+	_, ok := r.unloaded[code]
+
+	return ok
+}
+
+// readOptions reads only the options of the dictionary without decoding the spellchecker.
+// The info has the version and the checksum of the file if it has the header.
+func readOptions(r io.Reader) (Options, FileInfo, error) {
+	br := bufio.NewReader(r)
+
+	header, ok, err := readHeader(br)
+	if err != nil {
+		return Options{}, FileInfo{}, err
+	}
+
+	var info FileInfo
+	if ok {
+		info = FileInfo{Version: header.version, Checksum: header.checksum}
+	}
+
+	if ok && header.version == formatBinary {
+		options, err := readBinaryOptions(br, header)
+
+		return options, info, err
+	}
+
+	if ok && header.version != formatJSON {
+		return Options{}, FileInfo{}, fmt.Errorf("%w: version %d", ErrUnsupportedFormat, header.version)
+	}
+
+	dec := json.NewDecoder(br)
+
+	if t, err := dec.Token(); err != nil {
+		return Options{}, FileInfo{}, err
+	} else if t != json.Delim('{') {
+		return Options{}, FileInfo{}, fmt.Errorf("unexpected token %v", t)
+	}
+
+	// the options are written first, so the rest of the file is not read
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return Options{}, FileInfo{}, err
+		}
+
+		if t != "options" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return Options{}, FileInfo{}, err
+			}
+
+			continue
+		}
+
+		var result Options
+		if err := dec.Decode(&result); err != nil {
+			return Options{}, FileInfo{}, err
+		}
+
+		return result, info, nil
+	}
+
+	return Options{}, FileInfo{}, errors.New("options not found")
+}
+
+// readBinaryOptions decompresses only the section of the file
+func readBinaryOptions(r io.Reader, header fileHeader) (Options, error) {
+	compression, err := compressionByFlags(header.flags)
+	if err != nil {
+		return Options{}, err
+	}
+
+	dr, err := decompressReader(io.LimitReader(r, int64(header.size)), compression)
+	if err != nil {
+		return Options{}, err
+	}
+	defer dr.Close()
+
+	s, err := readSection(dr)
+	if err != nil {
+		return Options{}, err
+	}
+
+	return s.Options, nil
+}
@@ -0,0 +1,221 @@
+package spellchecker
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_LazyLoading(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	for _, code := range []string{"a", "b", "c"} {
+		_, err := r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", Language: "en"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.SaveAll(context.Background()))
+
+	info, err := os.Stat(fullPath(dir, "a"))
+	require.NoError(t, err)
+
+	// enough for two dictionaries only
+	r, err = NewRegistry(context.Background(), dir, WithLazyLoading(2*info.Size()))
+	require.NoError(t, err)
+	require.Empty(t, r.items)
+
+	list := r.List()
+	require.Len(t, list, 3)
+	require.Equal(t, "en", list[0].Options.Language)
+
+	t.Run("loaded on first use", func(t *testing.T) {
+		a, err := r.GetItem("a")
+		require.NoError(t, err)
+		a.AddWeight(1, "hello")
+		a.Release()
+
+		b, err := r.GetItem("b")
+		require.NoError(t, err)
+		b.Release()
+
+		stats := r.Stats()
+		require.Equal(t, 3, stats.Registered)
+		require.Equal(t, 2, stats.Loaded)
+		require.EqualValues(t, 2, stats.Loads)
+		require.Zero(t, stats.Evictions)
+	})
+
+	t.Run("least recently used is unloaded and saved", func(t *testing.T) {
+		c, err := r.GetItem("c")
+		require.NoError(t, err)
+		c.Release()
+
+		require.NotContains(t, r.items, "a")
+		require.Contains(t, r.items, "b")
+		require.Contains(t, r.items, "c")
+
+		stats := r.Stats()
+		require.Equal(t, 2, stats.Loaded)
+		require.EqualValues(t, 1, stats.Evictions)
+
+		a, err := r.GetItem("a")
+		require.NoError(t, err)
+		require.True(t, a.Spellchecker.IsCorrect("hello"))
+		require.NotContains(t, r.items, "b")
+		a.Release()
+	})
+
+	t.Run("unchanged dictionary is not saved", func(t *testing.T) {
+		before, err := os.Stat(fullPath(dir, "c"))
+		require.NoError(t, err)
+
+		b, err := r.GetItem("b")
+		require.NoError(t, err)
+		require.NotContains(t, r.items, "c")
+		b.Release()
+
+		after, err := os.Stat(fullPath(dir, "c"))
+		require.NoError(t, err)
+		require.Equal(t, before.ModTime(), after.ModTime())
+	})
+
+	t.Run("update and delete unloaded", func(t *testing.T) {
+		require.NotContains(t, r.items, "c")
+
+		options, err := r.Update("c", InfoUpdate{Owner: ptr("team")})
+		require.NoError(t, err)
+		require.Equal(t, "team", options.Owner)
+
+		require.NotContains(t, r.items, "a")
+		require.NoError(t, r.Delete("a", false))
+
+		_, err = r.GetItem("a")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Equal(t, 2, r.Stats().Registered)
+	})
+}
+
+func Test_Registry_LazyLoading_Pinned(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	for _, code := range []string{"a", "b"} {
+		_, err := r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.SaveAll(context.Background()))
+
+	info, err := os.Stat(fullPath(dir, "a"))
+	require.NoError(t, err)
+
+	// enough for one dictionary only
+	r, err = NewRegistry(context.Background(), dir, WithLazyLoading(info.Size()))
+	require.NoError(t, err)
+
+	a, err := r.GetItem("a")
+	require.NoError(t, err)
+
+	b, err := r.GetItem("b")
+	require.NoError(t, err)
+
+	// the dictionary used by a request is kept over the budget
+	require.Contains(t, r.items, "a")
+	a.AddWeight(1, "hello")
+	a.Release()
+	a.Release()
+	b.Release()
+
+	// the released dictionary is unloaded on the next request, the changes are saved
+	b, err = r.GetItem("b")
+	require.NoError(t, err)
+	require.NotContains(t, r.items, "a")
+	b.Release()
+
+	a, err = r.GetItem("a")
+	require.NoError(t, err)
+	require.NotContains(t, r.items, "b")
+	require.True(t, a.Spellchecker.IsCorrect("hello"))
+	a.Release()
+
+	require.EqualValues(t, 2, r.Stats().Evictions)
+	require.Zero(t, r.items["a"].pins.Load())
+}
+
+func Test_Registry_LazyLoading_Profile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	for _, code := range []string{"a", "b"} {
+		_, err := r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.SetAlias("alias", "a"))
+	require.NoError(t, r.SaveAll(context.Background()))
+
+	info, err := os.Stat(fullPath(dir, "a"))
+	require.NoError(t, err)
+
+	// enough for one dictionary only
+	r, err = NewRegistry(context.Background(), dir, WithLazyLoading(info.Size()))
+	require.NoError(t, err)
+
+	// the dictionary is loaded once to build the profile
+	code, profile, err := r.Profile("alias", "")
+	require.NoError(t, err)
+	require.Equal(t, "a", code)
+
+	_, _, err = r.Profile("b", "")
+	require.NoError(t, err)
+	require.NotContains(t, r.items, "a")
+	require.EqualValues(t, 2, r.Stats().Loads)
+
+	// the profile of the unloaded dictionary is kept
+	_, kept, err := r.Profile("a", "")
+	require.NoError(t, err)
+	require.Same(t, profile, kept)
+	require.NotContains(t, r.items, "a")
+	require.EqualValues(t, 2, r.Stats().Loads)
+
+	_, _, err = r.Profile("missing", "")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_readOptions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	_, err = r.Add("en", Options{Alphabet: "abc", MaxErrors: 2, Owner: "team"})
+	require.NoError(t, err)
+	require.NoError(t, r.Save("en"))
+
+	options, _, err := readOptions(openFile(t, fullPath(dir, "en")))
+	require.NoError(t, err)
+	require.Equal(t, "abc", options.Alphabet)
+	require.EqualValues(t, 2, options.MaxErrors)
+	require.Equal(t, "team", options.Owner)
+
+	require.NoError(t, os.WriteFile(fullPath(dir, "broken"), []byte(`{"spellchecker":""}`), 0644))
+	_, _, err = readOptions(openFile(t, fullPath(dir, "broken")))
+	require.Error(t, err)
+}
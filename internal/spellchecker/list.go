@@ -1,22 +1,39 @@
 package spellchecker
 
+import (
+	"slices"
+	"strings"
+)
+
 type ListItem struct {
 	Code    string
 	Aliases []string
+	Options Options
 }
 
 func (r *Registry) List() []ListItem {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	result := make([]ListItem, 0, len(r.items))
+	result := make([]ListItem, 0, len(r.items)+len(r.unloaded))
+
+	for code, item := range r.items {
+		result = append(result, ListItem{
+			Code:    code,
+			Aliases: r.metadata.InvertedAliases[code],
+			Options: item.Options,
+		})
+	}
 
-	for code := range r.items {
+	for code, u := range r.unloaded {
 		result = append(result, ListItem{
 			Code:    code,
 			Aliases: r.metadata.InvertedAliases[code],
+			Options: u.options,
 		})
 	}
 
+	slices.SortFunc(result, func(a, b ListItem) int { return strings.Compare(a.Code, b.Code) })
+
 	return result
 }
@@ -2,25 +2,41 @@ package spellchecker
 
 import (
 	"fmt"
+	"maps"
 	"slices"
+	"strings"
+	"time"
 )
 
-const metadataFile = "metadata"
-
 var (
 	ErrAliasNotFound = fmt.Errorf("alias not found")
 )
 
 type Metadata struct {
-	Aliases         map[string]string   `json:"aliases"`         // alias => dict
-	InvertedAliases map[string][]string `json:"invertedAliases"` // dict => aliases
+	Aliases         map[string]string        `json:"aliases"`          // alias => dict or alias
+	InvertedAliases map[string][]string      `json:"invertedAliases"`  // dict or alias => aliases
+	Splits          map[string][]AliasTarget `json:"splits,omitempty"` // alias => weighted targets, the alias points to the first one
+	Log             []AliasChange            `json:"log,omitempty"`    // alias changes, the oldest first
 }
 
 func newMetadata() Metadata {
 	return Metadata{
 		Aliases:         make(map[string]string),
 		InvertedAliases: make(map[string][]string),
+		Splits:          make(map[string][]AliasTarget),
+	}
+}
+
+// checkSplits validates the targets of the aliases with several targets the same way SetAliasTargets does,
+// e.g. for the metadata read from a file or received from the replication leader
+func (m Metadata) checkSplits() error {
+	for _, alias := range slices.Sorted(maps.Keys(m.Splits)) {
+		if err := checkTargets(m.Splits[alias]); err != nil {
+			return fmt.Errorf("alias %q: %w", alias, err)
+		}
 	}
+
+	return nil
 }
 
 func (r *Registry) ListAliases() []ListItem {
@@ -39,27 +55,25 @@ func (r *Registry) GetCodeByAlias(alias string) (string, error) {
 	return v, nil
 }
 
+// SetAlias points the alias to the dictionary or to another alias
 func (r *Registry) SetAlias(alias string, to string) error {
+	return r.SetAliasTargets(alias, []AliasTarget{{Dictionary: to}})
+}
+
+// SetAliasTargets points the alias to one or several dictionaries (or aliases).
+// Requests to an alias with several targets are split between them by weights.
+func (r *Registry) SetAliasTargets(alias string, targets []AliasTarget) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	_, ok := r.items[to]
-	if !ok {
-		return ErrAliasNotFound
+	if err := r.doCheckTargets(alias, targets, ErrAliasNotFound); err != nil {
+		return err
 	}
 
-	existing, ok := r.metadata.Aliases[alias]
-	if ok {
-		if existing == to {
-			return nil
-		}
-
-		_ = r.doDeleteAlias(alias)
+	if !r.doSetAlias(alias, targets, time.Now().UTC()) {
+		return nil
 	}
 
-	r.metadata.Aliases[alias] = to
-	r.metadata.InvertedAliases[to] = append(r.metadata.InvertedAliases[to], alias)
-
 	if err := r.doSaveMetadata(); err != nil {
 		return err
 	}
@@ -71,11 +85,18 @@ func (r *Registry) DeleteAlias(alias string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	err := r.doDeleteAlias(alias)
-	if err != nil {
-		return err
+	existing, ok := r.metadata.Aliases[alias]
+	if !ok {
+		return ErrAliasNotFound
 	}
 
+	if chained := r.metadata.InvertedAliases[alias]; len(chained) > 0 {
+		return fmt.Errorf("%w: %s", ErrHasAliases, strings.Join(chained, ", "))
+	}
+
+	_ = r.doDeleteAlias(alias)
+	r.doLog(AliasChange{Alias: alias, From: existing, Time: time.Now().UTC()})
+
 	if err := r.doSaveMetadata(); err != nil {
 		return err
 	}
@@ -83,25 +104,85 @@ func (r *Registry) DeleteAlias(alias string) error {
 	return nil
 }
 
-func (r *Registry) doDeleteAlias(alias string) error {
+// doSetAlias points the alias to the targets and records the change. Returns false if the alias already points to them.
+func (r *Registry) doSetAlias(alias string, targets []AliasTarget, now time.Time) bool {
+	if len(targets) == 1 {
+		targets = []AliasTarget{{Dictionary: targets[0].Dictionary}}
+	}
+
 	existing, ok := r.metadata.Aliases[alias]
-	if !ok {
-		return ErrAliasNotFound
+	if ok {
+		if slices.Equal(r.doAliasTargets(alias), targets) {
+			return false
+		}
+
+		_ = r.doDeleteAlias(alias)
 	}
 
-	delete(r.metadata.Aliases, alias)
+	to := targets[0].Dictionary
+	r.metadata.Aliases[alias] = to
+
+	change := AliasChange{Alias: alias, From: existing, To: to, Time: now}
+
+	if len(targets) > 1 {
+		r.metadata.Splits[alias] = slices.Clone(targets)
+		change.Targets = slices.Clone(targets)
+	}
+
+	for _, t := range targets {
+		r.metadata.InvertedAliases[t.Dictionary] = append(r.metadata.InvertedAliases[t.Dictionary], alias)
+	}
+
+	r.doLog(change)
+
+	return true
+}
 
-	for i, a := range r.metadata.InvertedAliases[existing] {
-		if a != alias {
-			continue
+// doRemoveTarget removes the dictionary (or the alias) from the targets of the aliases pointing to it.
+// The aliases left without targets are removed along with the aliases pointing to them.
+func (r *Registry) doRemoveTarget(name string, now time.Time) {
+	for _, alias := range slices.Clone(r.metadata.InvertedAliases[name]) {
+		if r.doRemoveAliasTarget(alias, name, now) {
+			r.doRemoveTarget(alias, now)
 		}
+	}
+}
+
+// doRemoveAliasTarget removes the target from the alias, the alias is removed if it has no targets left.
+// Returns true if the alias is removed.
+func (r *Registry) doRemoveAliasTarget(alias string, name string, now time.Time) bool {
+	targets := slices.DeleteFunc(slices.Clone(r.doAliasTargets(alias)), func(t AliasTarget) bool {
+		return t.Dictionary == name
+	})
+
+	if len(targets) > 0 {
+		r.doSetAlias(alias, targets, now)
+		return false
+	}
+
+	r.doLog(AliasChange{Alias: alias, From: r.metadata.Aliases[alias], Time: now})
+	_ = r.doDeleteAlias(alias)
+
+	return true
+}
 
-		r.metadata.InvertedAliases[existing] = slices.Delete(r.metadata.InvertedAliases[existing], i, i+1)
+func (r *Registry) doDeleteAlias(alias string) error {
+	if _, ok := r.metadata.Aliases[alias]; !ok {
+		return ErrAliasNotFound
+	}
 
-		if len(r.metadata.InvertedAliases[existing]) == 0 {
-			delete(r.metadata.InvertedAliases, existing)
+	for _, t := range r.doAliasTargets(alias) {
+		r.metadata.InvertedAliases[t.Dictionary] = slices.DeleteFunc(r.metadata.InvertedAliases[t.Dictionary], func(a string) bool {
+			return a == alias
+		})
+
+		if len(r.metadata.InvertedAliases[t.Dictionary]) == 0 {
+			delete(r.metadata.InvertedAliases, t.Dictionary)
 		}
 	}
 
+	delete(r.metadata.Aliases, alias)
+	delete(r.metadata.Splits, alias)
+
 	return nil
 }
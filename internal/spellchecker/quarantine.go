@@ -0,0 +1,218 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/storage"
+)
+
+const (
+	quarantineDir   = "quarantine"
+	reportExtension = ".json"
+)
+
+// FailedDictionary is a dictionary which failed to load
+type FailedDictionary struct {
+	Code        string    `json:"code"`
+	Error       string    `json:"error"`
+	Time        time.Time `json:"time"`
+	Quarantined bool      `json:"quarantined"` // the file is moved to the quarantine directory
+}
+
+// WithLoadWorkers sets the number of dictionaries loaded concurrently on startup. The number of CPUs is used by default.
+func WithLoadWorkers(n int) RegistryOption {
+	return func(r *Registry) {
+		if n > 0 {
+			r.workers = n
+		}
+	}
+}
+
+// Failed returns the dictionaries which failed to load sorted by code
+func (r *Registry) Failed() []FailedDictionary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.SortedFunc(maps.Values(r.failed), func(a, b FailedDictionary) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+}
+
+// loadResult is the result of loading a dictionary file on startup
+type loadResult struct {
+	code    string
+	item    RegistryItem
+	options Options
+	info    FileInfo
+	took    time.Duration
+	err     error
+}
+
+// loadAll loads the dictionaries concurrently (or only reads their options in the lazy mode)
+func (r *Registry) loadAll(codes []string) []loadResult {
+	results := make([]loadResult, len(codes))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(r.workers, len(codes)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i] = r.loadFile(codes[i])
+			}
+		}()
+	}
+
+	for i := range codes {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func (r *Registry) loadFile(code string) loadResult {
+	start := time.Now()
+	result := loadResult{code: code}
+
+	if r.lazy {
+		result.options, result.info, result.err = r.readOptions(code)
+	} else {
+		result.item, result.info, result.err = r.doLoad(code)
+	}
+
+	result.took = time.Since(start)
+
+	return result
+}
+
+// doFail records the load error. A file which can be read but not decoded is moved to the quarantine directory
+// along with the error report, so it is not loaded again. Files of a newer format version are left as is,
+// nothing is moved in the read-only mode.
+// The wrapped load error is returned.
+func (r *Registry) doFail(code string, err error) error {
+	r.cache.failed()
+
+	failure := FailedDictionary{
+		Code:  code,
+		Error: err.Error(),
+		Time:  time.Now().UTC(),
+	}
+
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) && !errors.Is(err, ErrUnsupportedFormat) && !r.readOnly {
+		if qErr := r.doQuarantine(failure); qErr != nil {
+			r.log.Error("registry: dictionary quarantine error", "dictionary", code, "error", qErr)
+		} else {
+			failure.Quarantined = true
+			delete(r.unloaded, code)
+		}
+	}
+
+	r.log.Error("registry: dictionary load error", "dictionary", code, "error", err, "quarantined", failure.Quarantined)
+
+	r.failed[code] = failure
+
+	return fmt.Errorf("dictionary %q load: %w", code, err)
+}
+
+func (r *Registry) doQuarantine(failure FailedDictionary) error {
+	name := fileName(failure.Code)
+
+	if err := r.copyFile(name, path.Join(quarantineDir, name)); err != nil {
+		return err
+	}
+
+	if err := r.storage.Delete(name); err != nil {
+		return err
+	}
+
+	failure.Quarantined = true
+
+	data, err := json.Marshal(failure)
+	if err == nil {
+		err = r.storage.Write(path.Join(quarantineDir, failure.Code+reportExtension), func(w storage.Writer) error {
+			_, err := w.Write(data)
+
+			return err
+		})
+	}
+
+	if err != nil {
+		r.log.Error("registry: quarantine report write error", "dictionary", failure.Code, "error", err)
+	}
+
+	return nil
+}
+
+// loadQuarantine reads the reports of the dictionaries quarantined before
+func (r *Registry) loadQuarantine() error {
+	files, err := r.storage.List(quarantineDir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name, reportExtension) {
+			continue
+		}
+
+		data, err := r.readReport(f.Name)
+		if err != nil {
+			return err
+		}
+
+		var failure FailedDictionary
+		if err := json.Unmarshal(data, &failure); err != nil {
+			r.log.Error("registry: quarantine report read error", "file", f.Name, "error", err)
+			continue
+		}
+
+		r.failed[failure.Code] = failure
+	}
+
+	return nil
+}
+
+func (r *Registry) readReport(name string) ([]byte, error) {
+	f, err := r.storage.Open(path.Join(quarantineDir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// doForgetFailure removes the failure of the dictionary which has been loaded successfully (e.g. the file was fixed)
+func (r *Registry) doForgetFailure(code string) {
+	failure, ok := r.failed[code]
+	if !ok {
+		return
+	}
+
+	delete(r.failed, code)
+
+	if failure.Quarantined && !r.readOnly {
+		err := r.storage.Delete(path.Join(quarantineDir, code+reportExtension))
+		if err != nil {
+			r.log.Error("registry: quarantine report remove error", "dictionary", code, "error", err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package spellchecker
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewRegistry_Quarantine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, code := range []string{"a", "b", "c", "d"} {
+		createTestFile(t, dir, code)
+	}
+
+	require.NoError(t, os.WriteFile(fullPath(dir, "broken"), []byte("qweqwe"), 0644))
+
+	r, err := NewRegistry(context.Background(), dir, WithLoadWorkers(3))
+	require.NoError(t, err)
+	require.Len(t, r.items, 4)
+	require.NotContains(t, r.items, "broken")
+
+	failed := r.Failed()
+	require.Len(t, failed, 1)
+	require.Equal(t, "broken", failed[0].Code)
+	require.True(t, failed[0].Quarantined)
+	require.NotEmpty(t, failed[0].Error)
+
+	require.NoFileExists(t, fullPath(dir, "broken"))
+	require.FileExists(t, path.Join(dir, quarantineDir, fileName("broken")))
+	require.EqualValues(t, 1, r.Stats().LoadErrors)
+
+	t.Run("reported after restart", func(t *testing.T) {
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+		require.Equal(t, failed, r.Failed())
+	})
+
+	t.Run("forgotten when fixed", func(t *testing.T) {
+		require.NoError(t, os.Remove(path.Join(dir, quarantineDir, fileName("broken"))))
+		createTestFile(t, dir, "broken")
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+		require.Contains(t, r.items, "broken")
+		require.Empty(t, r.Failed())
+		require.NoFileExists(t, path.Join(dir, quarantineDir, "broken"+reportExtension))
+	})
+}
+
+func Test_Registry_LazyLoading_Quarantine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// the options are valid, but the spellchecker is not
+	require.NoError(t, os.WriteFile(fullPath(dir, "broken"), []byte(`{"options":{"alphabet":"abc"},"spellchecker":"cXdl"}`), 0644))
+
+	r, err := NewRegistry(context.Background(), dir, WithLazyLoading(0))
+	require.NoError(t, err)
+	require.Empty(t, r.Failed())
+
+	_, err = r.GetItem("broken")
+	require.Error(t, err)
+
+	_, err = r.GetItem("broken")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	failed := r.Failed()
+	require.Len(t, failed, 1)
+	require.True(t, failed[0].Quarantined)
+}
@@ -0,0 +1,38 @@
+package spellchecker
+
+import (
+	"fmt"
+
+	"github.com/f1monkey/spellchecker-web/internal/storage"
+)
+
+var ErrReadOnly = fmt.Errorf("registry is read-only")
+
+// WithReadOnly never writes to the storage: the dictionaries are not saved, migrated or quarantined,
+// the operations log is disabled. The storage may be mounted read-only.
+func WithReadOnly() RegistryOption {
+	return func(r *Registry) {
+		r.readOnly = true
+	}
+}
+
+// readOnlyStorage rejects all the writes, so nothing is written even by a mistake
+type readOnlyStorage struct {
+	storage.Storage
+}
+
+func (readOnlyStorage) Write(name string, write func(w storage.Writer) error) error {
+	return ErrReadOnly
+}
+
+func (readOnlyStorage) Delete(name string) error {
+	return ErrReadOnly
+}
+
+func (readOnlyStorage) Rename(from string, to string) error {
+	return ErrReadOnly
+}
+
+func (readOnlyStorage) PutMetadata(data []byte) error {
+	return ErrReadOnly
+}
@@ -0,0 +1,66 @@
+package spellchecker
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	dir, walDir := t.TempDir(), t.TempDir()
+
+	// a legacy file is migrated, a broken file is quarantined and a broken alias is removed in the normal mode
+	createTestFile(t, dir, "legacy")
+	require.NoError(t, os.WriteFile(fullPath(dir, "broken"), []byte("garbage"), 0644))
+
+	m := newMetadata()
+	m.Aliases["alias"] = "missing"
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(dir, "metadata"), encodeFile(data), 0644))
+
+	before := readDir(t, dir)
+
+	r, err := NewRegistry(context.Background(), dir, WithReadOnly(), WithWAL(walDir, WALSyncAlways))
+	require.NoError(t, err)
+
+	_, err = r.GetItem("legacy")
+	require.NoError(t, err)
+	require.Len(t, r.Failed(), 1)
+
+	require.ErrorIs(t, r.Save("legacy"), ErrReadOnly)
+	require.ErrorIs(t, r.SaveAll(context.Background()), ErrReadOnly)
+
+	require.Equal(t, before, readDir(t, dir))
+	require.Empty(t, readDir(t, walDir))
+}
+
+// readDir returns the names and the contents of the files in the directory
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	result := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			result[e.Name()] = "dir"
+			continue
+		}
+
+		data, err := os.ReadFile(path.Join(dir, e.Name()))
+		require.NoError(t, err)
+
+		result[e.Name()] = string(data)
+	}
+
+	return result
+}
@@ -3,20 +3,27 @@ package spellchecker
 import (
 	"context"
 	"fmt"
-	"io/fs"
-	"os"
-	"path"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/f1monkey/spellchecker"
 	"github.com/f1monkey/spellchecker-web/internal/logger"
+	"github.com/f1monkey/spellchecker-web/internal/phonetic"
+	"github.com/f1monkey/spellchecker-web/internal/storage"
 )
 
 var (
 	ErrAlreadyExists    = fmt.Errorf("dictionary already exists")
 	ErrSpellcheckerInit = fmt.Errorf("spellchecker init err")
 	ErrNotFound         = fmt.Errorf("dictionary not found")
+	ErrInvalidOptions   = fmt.Errorf("invalid dictionary options")
+	ErrHasAliases       = fmt.Errorf("dictionary has aliases")
 )
 
 const extension = ".dict"
@@ -25,19 +32,83 @@ type Registry struct {
 	mu sync.RWMutex
 
 	metadata Metadata
-	dir      string
+	storage  storage.Storage
 	items    map[string]RegistryItem
+	log      *slog.Logger
+
+	compression Compression
+
+	versionsMu sync.Mutex
+	versions   int
+
+	workers int
+	failed  map[string]FailedDictionary
+
+	lazy     bool
+	budget   int64
+	unloaded map[string]unloadedItem // registered dictionaries not loaded yet (lazy loading)
+	loadMu   sync.Mutex
+	cache    *cache
+
+	walDir  string // operations log is disabled if empty
+	walSync WALSync
+
+	readOnly bool
+
+	restoreLimit int64
+
+	reloadMu    sync.Mutex
+	seen        map[string]storage.File // files of the storage as of the last reload
+	checksumsMu sync.Mutex
+	checksums   map[string]uint32 // checksums of the dictionary files written or read by the registry
 }
 
-func NewRegistry(ctx context.Context, dir string) (*Registry, error) {
-	files, err := findDictionaries(dir)
-	if err != nil {
-		return nil, err
+type RegistryOption func(r *Registry)
+
+// WithVersions keeps the last n versions of a dictionary on each save. 0 - disabled.
+func WithVersions(n int) RegistryOption {
+	return func(r *Registry) {
+		r.versions = n
+	}
+}
+
+// WithStorage sets the storage of the dictionaries instead of the local directory passed to NewRegistry
+func WithStorage(s storage.Storage) RegistryOption {
+	return func(r *Registry) {
+		r.storage = s
 	}
+}
 
+func NewRegistry(ctx context.Context, dir string, opts ...RegistryOption) (*Registry, error) {
 	result := &Registry{
-		dir:   dir,
-		items: make(map[string]RegistryItem),
+		storage:  storage.NewLocal(dir),
+		items:    make(map[string]RegistryItem),
+		log:      logger.FromContext(ctx),
+		unloaded: make(map[string]unloadedItem),
+		cache:    newCache(),
+		workers:  runtime.NumCPU(),
+		failed:   make(map[string]FailedDictionary),
+
+		seen:      make(map[string]storage.File),
+		checksums: make(map[string]uint32),
+
+		compression:  CompressionNone,
+		walSync:      WALSyncAlways,
+		restoreLimit: DefaultRestoreLimit,
+	}
+
+	for _, o := range opts {
+		o(result)
+	}
+
+	if result.readOnly {
+		result.storage = readOnlyStorage{result.storage}
+		result.walDir = ""
+	}
+
+	files, err := result.findDictionaries()
+	if err != nil {
+		return nil, err
 	}
 
 	metadata, err := result.doLoadMetadata()
@@ -47,31 +118,136 @@ func NewRegistry(ctx context.Context, dir string) (*Registry, error) {
 
 	result.metadata = metadata
 
+	if err := result.loadQuarantine(); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(files))
 	for _, f := range files {
-		code, _ := strings.CutSuffix(f.Name(), extension)
+		code, _ := strings.CutSuffix(f.Name, extension)
+		codes = append(codes, code)
+		result.seen[f.Name] = f
+	}
 
-		item, err := result.doLoad(code)
-		if err != nil {
-			logger.FromContext(ctx).Error("registry: dictionary load error", "code", code, "error", err)
+	for _, res := range result.loadAll(codes) {
+		if res.err != nil {
+			_ = result.doFail(res.code, res.err)
 			continue
 		}
 
-		logger.FromContext(ctx).Info("registry: loaded dictionary", "dictionary", code)
+		result.doForgetFailure(res.code)
 
-		result.items[code] = item
+		if result.lazy {
+			result.unloaded[res.code] = unloadedItem{options: res.options}
+
+			if res.info.Version != 0 {
+				result.setChecksum(res.code, res.info.Checksum)
+			}
+
+			continue
+		}
+
+		logger.FromContext(ctx).Info("registry: loaded dictionary", "dictionary", res.code, "duration", res.took)
+
+		result.items[res.code] = res.item
+		result.cache.add(res.code, res.info.DecodedSize, res.item.revision())
+		result.cache.loaded(res.took)
+		result.setChecksum(res.code, res.info.Checksum)
+		result.doOpenLog(res.code)
+		result.doMigrate(res.code, res.info)
+	}
+
+	if result.lazy {
+		logger.FromContext(ctx).Info("registry: registered dictionaries", "count", len(result.unloaded))
+	}
+
+	result.repairAliases(ctx)
+
+	if result.walDir != "" && result.walSync == WALSyncInterval {
+		go result.syncLogs(ctx)
 	}
 
 	return result, nil
 }
 
+// repairAliases removes aliases pointing to dictionaries (or aliases) which do not exist anymore.
+// Aliases of the dictionaries failed to load are kept, so they work again once the dictionary file is fixed.
+func (r *Registry) repairAliases(ctx context.Context) {
+	now := time.Now().UTC()
+	changed := false
+
+	// removing an alias may break the aliases pointing to it, so repeat until nothing is removed
+	for removed := true; removed; {
+		removed = false
+
+		for _, alias := range slices.Sorted(maps.Keys(r.metadata.Aliases)) {
+			for _, t := range r.doAliasTargets(alias) {
+				if r.doTargetExists(t.Dictionary) {
+					continue
+				}
+
+				if _, ok := r.failed[t.Dictionary]; ok {
+					logger.FromContext(ctx).Warn("registry: alias points to a dictionary failed to load", "alias", alias, "dictionary", t.Dictionary)
+					continue
+				}
+
+				logger.FromContext(ctx).Warn("registry: removed missing dictionary from alias targets", "alias", alias, "dictionary", t.Dictionary)
+
+				r.doRemoveAliasTarget(alias, t.Dictionary, now)
+				changed, removed = true, true
+
+				break
+			}
+		}
+	}
+
+	if !changed || r.readOnly {
+		return
+	}
+
+	if err := r.doSaveMetadata(); err != nil {
+		logger.FromContext(ctx).Error("registry: metadata save error", "error", err)
+	}
+}
+
+// validFileCode checks that the code can be used as a file name
+func validFileCode(code string) bool {
+	return code != "" && !strings.ContainsAny(code, `/\`) && !strings.HasPrefix(code, ".")
+}
+
 func (r *Registry) Add(code string, options Options) (*spellchecker.Spellchecker, error) {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	result, err := r.add(code, options)
+	if err != nil {
+		return nil, err
+	}
+
+	r.evict(code)
+
+	return result, nil
+}
+
+func (r *Registry) add(code string, options Options) (*spellchecker.Spellchecker, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.items[code]; ok {
+	if !validFileCode(code) {
+		return nil, fmt.Errorf("%w: code %q cannot be used as a file name", ErrInvalidOptions, code)
+	}
+
+	if r.doExists(code) {
 		return nil, ErrAlreadyExists
 	}
 
+	if err := validateLanguage(options.Language); err != nil {
+		return nil, err
+	}
+
+	options.CreatedAt = time.Now().UTC()
+	options.UpdatedAt = options.CreatedAt
+
 	result, err := spellchecker.New(
 		options.Alphabet,
 		spellchecker.WithMaxErrors(int(options.MaxErrors)),
@@ -80,63 +256,129 @@ func (r *Registry) Add(code string, options Options) (*spellchecker.Spellchecker
 		return nil, ErrSpellcheckerInit
 	}
 
-	r.items[code] = RegistryItem{
+	item := RegistryItem{
 		Spellchecker: result,
+		Feedback:     NewFeedback(),
+		Rules:        NewRules(),
+		Language:     NewLanguage(),
+		Words:        NewWords(),
 		Options:      options,
+		id:           itemIDs.Add(1),
+		changes:      new(atomic.Uint64),
+		pins:         new(atomic.Int64),
+	}
+
+	if options.Candidates != nil {
+		item.Candidates = NewCandidates()
+	}
+
+	if options.Phonetic != "" {
+		item.Phonetic, err = phonetic.NewIndex(options.Phonetic)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidOptions, err)
+		}
+	}
+
+	r.items[code] = item
+
+	// the new dictionary is not saved yet
+	r.cache.add(code, 0, item.revision())
+	item.changed()
+
+	// the operations log is replayed on top of the saved dictionary, so the new dictionary is saved right away
+	if r.walDir != "" {
+		err := r.doRemoveLog(code)
+		if err == nil {
+			err = r.doSave(code, false)
+		}
+
+		if err != nil {
+			delete(r.items, code)
+			r.cache.remove(code, false)
+
+			return nil, err
+		}
+
+		r.doOpenLog(code)
 	}
 
 	return result, nil
 }
 
 func (r *Registry) Get(code string) (*spellchecker.Spellchecker, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	v, ok := r.items[code]
-	if !ok {
-		aliased, ok := r.metadata.Aliases[code]
-		if !ok {
-			return nil, ErrNotFound
-		}
-
-		v = r.items[aliased]
+	item, err := r.GetItem(code)
+	if err != nil {
+		return nil, err
 	}
+	defer item.Release()
 
-	return v.Spellchecker, nil
+	return item.Spellchecker, nil
 }
 
-func (r *Registry) Delete(code string) error {
+// GetItem returns the dictionary by its code or alias.
+// An alias with several targets is resolved to the first one. The dictionary must be released after use.
+func (r *Registry) GetItem(code string) (RegistryItem, error) {
+	_, item, err := r.resolve(code, func(alias string, targets []AliasTarget) string {
+		return targets[0].Dictionary
+	})
+
+	return item, err
+}
+
+// Delete removes the dictionary. A dictionary with aliases is removed only if cascade is true:
+// the dictionary is removed from the targets of its aliases, the aliases left without targets are removed as well.
+func (r *Registry) Delete(code string, cascade bool) error {
+	// a dictionary being saved before unloading must not be written after the file is removed
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	_, ok := r.items[code]
-	if !ok {
+	if !r.doExists(code) {
 		return ErrNotFound
 	}
 
-	err := os.Remove(fullPath(r.dir, code))
-	if err != nil && !os.IsNotExist(err) {
+	aliases := slices.Clone(r.metadata.InvertedAliases[code])
+	if len(aliases) > 0 && !cascade {
+		return fmt.Errorf("%w: %s", ErrHasAliases, strings.Join(aliases, ", "))
+	}
+
+	if err := r.storage.Delete(fileName(code)); err != nil {
+		return err
+	}
+
+	if err := r.doDeleteVersions(code); err != nil {
+		return err
+	}
+
+	if err := r.doRemoveLog(code); err != nil {
 		return err
 	}
 
 	delete(r.items, code)
+	delete(r.unloaded, code)
+	r.cache.remove(code, false)
+	r.forgetChecksum(code)
+
+	if len(aliases) == 0 {
+		return nil
+	}
 
-	return nil
+	r.doRemoveTarget(code, time.Now().UTC())
+
+	return r.doSaveMetadata()
 }
 
-func findDictionaries(dir string) ([]fs.DirEntry, error) {
-	files, err := os.ReadDir(dir)
+func (r *Registry) findDictionaries() ([]storage.File, error) {
+	files, err := r.storage.List("")
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]fs.DirEntry, 0, len(files))
+	result := make([]storage.File, 0, len(files))
 	for _, f := range files {
-		if f.IsDir() {
-			continue
-		}
-
-		if !strings.HasSuffix(f.Name(), extension) {
+		if !strings.HasSuffix(f.Name, extension) {
 			continue
 		}
 
@@ -149,7 +391,3 @@ func findDictionaries(dir string) ([]fs.DirEntry, error) {
 func fileName(code string) string {
 	return code + extension
 }
-
-func fullPath(dir string, code string) string {
-	return path.Join(dir, fileName(code))
-}
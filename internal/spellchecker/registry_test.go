@@ -1,13 +1,18 @@
 package spellchecker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/f1monkey/spellchecker"
+	"github.com/f1monkey/spellchecker-web/internal/storage"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,7 +51,7 @@ func Test_NewRegistry(t *testing.T) {
 		createTestFile(t, dir, "code1")
 		createTestFile(t, dir, "code2")
 
-		err := os.WriteFile(path.Join(dir, metadataFile), []byte(`{}`), 0644)
+		err := os.WriteFile(path.Join(dir, storage.MetadataFile), []byte(`{}`), 0644)
 		require.NoError(t, err)
 
 		result, err := NewRegistry(context.Background(), dir)
@@ -119,6 +124,32 @@ func Test_Registry_Add(t *testing.T) {
 		require.ErrorIs(t, err, ErrSpellcheckerInit)
 	})
 
+	t.Run("invalid code", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		for _, code := range []string{"", "..", ".hidden", "a/b", `a\b`} {
+			_, err = r.Add(code, Options{Alphabet: "abc"})
+			require.ErrorIs(t, err, ErrInvalidOptions, code)
+		}
+
+		require.Empty(t, r.items)
+	})
+
+	t.Run("invalid phonetic algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abc", Phonetic: "qwerty"})
+
+		require.ErrorIs(t, err, ErrInvalidOptions)
+		require.NotContains(t, r.items, "code")
+	})
+
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
 
@@ -131,7 +162,25 @@ func Test_Registry_Add(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
-		require.Equal(t, opts, r.items["code"].Options)
+
+		saved := r.items["code"].Options
+		require.False(t, saved.CreatedAt.IsZero())
+		require.Equal(t, saved.CreatedAt, saved.UpdatedAt)
+
+		saved.CreatedAt, saved.UpdatedAt = time.Time{}, time.Time{}
+		require.Equal(t, opts, saved)
+	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abc", Language: "english language"})
+
+		require.ErrorIs(t, err, ErrInvalidOptions)
+		require.NotContains(t, r.items, "code")
 	})
 }
 
@@ -197,7 +246,7 @@ func Test_Registry_Delete(t *testing.T) {
 		_, err = r.Add("code", Options{Alphabet: "abc"})
 		require.NoError(t, err)
 
-		err = r.Delete("qwerty")
+		err = r.Delete("qwerty", false)
 		require.ErrorIs(t, err, ErrNotFound)
 	})
 
@@ -213,7 +262,7 @@ func Test_Registry_Delete(t *testing.T) {
 		_, err = r.Add(code, Options{Alphabet: "abc"})
 		require.NoError(t, err)
 
-		err = r.Delete(code)
+		err = r.Delete(code, false)
 		require.NoError(t, err)
 
 		require.NotContains(t, r.items, code)
@@ -235,7 +284,7 @@ func Test_Registry_Delete(t *testing.T) {
 		require.NoError(t, err)
 		require.FileExists(t, path.Join(dir, fileName(code)))
 
-		err = r.Delete(code)
+		err = r.Delete(code, false)
 		require.NoError(t, err)
 		require.NoFileExists(t, path.Join(dir, fileName(code)))
 
@@ -243,6 +292,88 @@ func Test_Registry_Delete(t *testing.T) {
 	})
 }
 
+func Test_Registry_Delete_Aliases(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		require.NoError(t, r.SetAlias("alias1", "code"))
+		require.NoError(t, r.SetAlias("alias2", "code"))
+
+		return r
+	}
+
+	t.Run("has aliases", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		err := r.Delete("code", false)
+		require.ErrorIs(t, err, ErrHasAliases)
+		require.Contains(t, r.items, "code")
+		require.Contains(t, r.metadata.Aliases, "alias1")
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t)
+
+		err := r.Delete("code", true)
+		require.NoError(t, err)
+		require.NotContains(t, r.items, "code")
+		require.Empty(t, r.metadata.Aliases)
+		require.Empty(t, r.metadata.InvertedAliases)
+
+		_, err = r.GetItem("alias1")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		// the metadata is saved
+		loaded := reopen(t, r)
+		require.Empty(t, loaded.metadata.Aliases)
+	})
+}
+
+func Test_NewRegistry_DanglingAliases(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	createTestFile(t, dir, "code")
+	require.NoError(t, os.WriteFile(path.Join(dir, fileName("broken")), []byte(`{`), 0644))
+
+	metadata := `{
+		"aliases": {"alias": "code", "missing-alias": "missing", "broken-alias": "broken"},
+		"invertedAliases": {"code": ["alias"], "missing": ["missing-alias"], "broken": ["broken-alias"]}
+	}`
+	require.NoError(t, os.WriteFile(path.Join(dir, storage.MetadataFile), []byte(metadata), 0644))
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	// aliases of missing dictionaries are removed
+	require.Equal(t, map[string]string{"alias": "code", "broken-alias": "broken"}, r.metadata.Aliases)
+	require.NotContains(t, r.metadata.InvertedAliases, "missing")
+	require.Len(t, r.AliasHistory("missing-alias"), 1)
+
+	// aliases of dictionaries failed to load are kept, but not resolved
+	_, err = r.GetItem("broken-alias")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = r.GetItem("alias")
+	require.NoError(t, err)
+
+	// the repaired metadata is saved
+	loaded, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, r.metadata.Aliases, loaded.metadata.Aliases)
+}
+
 func createTestFile(t *testing.T, dir string, name string) {
 	t.Helper()
 
@@ -259,3 +390,35 @@ func createTestFile(t *testing.T, dir string, name string) {
 	err = os.WriteFile(path.Join(dir, fileName(name)), data, 0755)
 	require.NoError(t, err)
 }
+
+func fullPath(dir string, code string) string {
+	return path.Join(dir, fileName(code))
+}
+
+func openFile(t *testing.T, filePath string) io.Reader {
+	t.Helper()
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	return bytes.NewReader(data)
+}
+
+// reopen creates a new registry with the storage of the registry
+func reopen(t *testing.T, r *Registry) *Registry {
+	t.Helper()
+
+	result, err := NewRegistry(context.Background(), "", WithStorage(r.storage))
+	require.NoError(t, err)
+
+	return result
+}
+
+// failingStorage fails to write the metadata
+type failingStorage struct {
+	storage.Storage
+}
+
+func (failingStorage) PutMetadata(data []byte) error {
+	return errors.New("write failed")
+}
@@ -0,0 +1,324 @@
+package spellchecker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/f1monkey/spellchecker-web/internal/logger"
+	"github.com/f1monkey/spellchecker-web/internal/storage"
+)
+
+// ReloadResult lists the changes of the registry made by Reload
+type ReloadResult struct {
+	Added    []string
+	Replaced []string
+	Removed  []string
+	Metadata bool     // the aliases are re-read
+	Errors   []string // files failed to load, the dictionaries in memory are kept
+}
+
+// Changed checks if the reload has changed anything
+func (r ReloadResult) Changed() bool {
+	return len(r.Added) > 0 || len(r.Replaced) > 0 || len(r.Removed) > 0 || r.Metadata
+}
+
+// Watch reloads the dictionaries changed in the storage periodically until the context is done
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := r.Reload(ctx)
+				if err != nil {
+					logger.FromContext(ctx).Error("registry: reload error", "error", err)
+				} else if len(result.Errors) > 0 {
+					logger.FromContext(ctx).Warn("registry: reload incomplete", "errors", result.Errors)
+				}
+			}
+		}
+	}()
+}
+
+// Reload applies the changes of the dictionary files and the metadata made outside of the registry.
+// New files are loaded, replaced ones are swapped in (the changes made since the last save are discarded),
+// the dictionaries which files are removed are unloaded. Only the files which modification time or size
+// have changed since the previous reload are read, the files written by the registry itself are skipped.
+func (r *Registry) Reload(ctx context.Context) (ReloadResult, error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	files, err := r.storage.List("")
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	var result ReloadResult
+
+	seen := make(map[string]storage.File, len(files))
+	metadataChanged := false
+
+	for _, f := range files {
+		seen[f.Name] = f
+
+		if prev, ok := r.seen[f.Name]; ok && prev.Size == f.Size && prev.ModTime.Equal(f.ModTime) {
+			continue
+		}
+
+		if f.Name == storage.MetadataFile {
+			metadataChanged = true
+			continue
+		}
+
+		code, ok := strings.CutSuffix(f.Name, extension)
+		if !ok || !validFileCode(code) {
+			continue
+		}
+
+		if err := r.reloadFile(code, &result); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("dictionary %q: %s", code, err))
+			forget(seen, r.seen, f.Name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(r.seen)) {
+		code, ok := strings.CutSuffix(name, extension)
+		if _, exists := seen[name]; exists || !ok {
+			continue
+		}
+
+		removed, err := r.reloadRemoved(code)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("dictionary %q: %s", code, err))
+			forget(seen, r.seen, name)
+		} else if removed {
+			result.Removed = append(result.Removed, code)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if metadataChanged {
+		result.Metadata, err = r.doReloadMetadata()
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("metadata: %s", err))
+			forget(seen, r.seen, storage.MetadataFile)
+		}
+	}
+
+	r.seen = seen
+
+	if len(result.Removed) > 0 || result.Metadata {
+		r.repairAliases(ctx)
+	}
+
+	if result.Changed() {
+		r.log.Info("registry: reloaded", "added", result.Added, "replaced", result.Replaced, "removed", result.Removed, "metadata", result.Metadata)
+	}
+
+	return result, nil
+}
+
+// forget restores the previous state of the file which failed to reload, so it is read again by the next reload
+func forget(seen map[string]storage.File, prev map[string]storage.File, name string) {
+	if f, ok := prev[name]; ok {
+		seen[name] = f
+	} else {
+		delete(seen, name)
+	}
+}
+
+// reloadFile loads the changed dictionary file and swaps it in. The file is decoded before the registry is changed,
+// so a broken file does not replace the dictionary in memory. A broken new file is quarantined as on startup.
+func (r *Registry) reloadFile(code string, result *ReloadResult) error {
+	sum, sumErr := r.fileChecksum(fileName(code))
+	if sumErr == nil && r.isOwnFile(code, sum) {
+		return nil
+	}
+
+	item, info, err := r.doLoad(code)
+
+	// a dictionary being loaded must not be inserted after the swap
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the file may be written by a save finished in the meantime
+	if sumErr == nil && r.isOwnFile(code, sum) {
+		return nil
+	}
+
+	exists := r.doExists(code)
+
+	if err != nil && !exists {
+		return r.doFail(code, err)
+	} else if err != nil {
+		return fmt.Errorf("%w, the dictionary in memory is kept", err)
+	}
+
+	d := restoredDictionary{options: item.Options, info: info}
+	if !r.lazy {
+		d.item = item
+	}
+
+	if err := r.doRestored(code, d); err != nil {
+		return err
+	}
+
+	if exists {
+		result.Replaced = append(result.Replaced, code)
+	} else {
+		result.Added = append(result.Added, code)
+	}
+
+	return nil
+}
+
+// reloadRemoved unloads the dictionary which file has been removed
+func (r *Registry) reloadRemoved(code string) (bool, error) {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.doExists(code) {
+		return false, nil
+	}
+
+	// the dictionary may be saved again in the meantime
+	f, err := r.storage.Open(fileName(code))
+	if err == nil {
+		f.Close()
+		return false, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+
+	if err := r.doRemoveLog(code); err != nil {
+		return false, err
+	}
+
+	delete(r.items, code)
+	delete(r.unloaded, code)
+	r.cache.remove(code, false)
+	r.forgetChecksum(code)
+
+	return true, nil
+}
+
+// doReloadMetadata replaces the metadata in memory with the content of the file if they differ
+func (r *Registry) doReloadMetadata() (bool, error) {
+	data, err := r.storage.GetMetadata()
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	m, err := decodeMetadata(data)
+	if err != nil {
+		return false, err
+	}
+
+	next, err := json.Marshal(m)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := json.Marshal(r.metadata)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(next, current) {
+		return false, nil
+	}
+
+	m.rebuildInverted()
+	r.metadata = m
+
+	return true, nil
+}
+
+// fileChecksum returns the checksum of the file content. It is read from the header, only legacy files are read entirely.
+func (r *Registry) fileChecksum(name string) (uint32, error) {
+	f, err := r.storage.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+
+	header, ok, err := readHeader(br)
+	if err != nil {
+		return 0, err
+	} else if ok {
+		return header.checksum, nil
+	}
+
+	crc := crc32.New(crcTable)
+	if _, err := io.Copy(crc, br); err != nil {
+		return 0, err
+	}
+
+	return crc.Sum32(), nil
+}
+
+// setChecksum remembers the checksum of the dictionary file written or read by the registry
+func (r *Registry) setChecksum(code string, sum uint32) {
+	r.checksumsMu.Lock()
+	defer r.checksumsMu.Unlock()
+
+	r.checksums[code] = sum
+}
+
+func (r *Registry) forgetChecksum(code string) {
+	r.checksumsMu.Lock()
+	defer r.checksumsMu.Unlock()
+
+	delete(r.checksums, code)
+}
+
+// checksum returns the checksum of the file written or read by the registry
+func (r *Registry) checksum(code string) (uint32, bool) {
+	r.checksumsMu.Lock()
+	defer r.checksumsMu.Unlock()
+
+	sum, ok := r.checksums[code]
+
+	return sum, ok
+}
+
+// isOwnFile checks if the file with the checksum has been written or read by the registry
+func (r *Registry) isOwnFile(code string, sum uint32) bool {
+	r.checksumsMu.Lock()
+	defer r.checksumsMu.Unlock()
+
+	own, ok := r.checksums[code]
+
+	return ok && own == sum
+}
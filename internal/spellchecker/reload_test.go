@@ -0,0 +1,256 @@
+package spellchecker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dropFile builds the dictionary with the words in another directory and moves its file to the directory, as a build pipeline does
+func dropFile(t *testing.T, dir string, code string, words ...string) {
+	t.Helper()
+
+	tmp := t.TempDir()
+
+	r, err := NewRegistry(context.Background(), tmp)
+	require.NoError(t, err)
+
+	_, err = r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+	require.NoError(t, err)
+
+	item, err := r.GetItem(code)
+	require.NoError(t, err)
+	item.AddWeight(1, words...)
+
+	require.NoError(t, r.Save(code))
+	require.NoError(t, os.Rename(fullPath(tmp, code), fullPath(dir, code)))
+}
+
+func Test_Registry_Reload(t *testing.T) {
+	t.Parallel()
+
+	for _, lazy := range []bool{false, true} {
+		var opts []RegistryOption
+		if lazy {
+			opts = append(opts, WithLazyLoading(0))
+		}
+
+		t.Run(fmt.Sprintf("added and replaced, lazy %v", lazy), func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			dropFile(t, dir, "code", "apple")
+
+			r, err := NewRegistry(context.Background(), dir, opts...)
+			require.NoError(t, err)
+
+			dropFile(t, dir, "new", "banana")
+			dropFile(t, dir, "code", "cherry")
+
+			result, err := r.Reload(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []string{"new"}, result.Added)
+			require.Equal(t, []string{"code"}, result.Replaced)
+			require.Empty(t, result.Errors)
+
+			item, err := r.GetItem("new")
+			require.NoError(t, err)
+			require.True(t, item.Spellchecker.IsCorrect("banana"))
+
+			item, err = r.GetItem("code")
+			require.NoError(t, err)
+			require.True(t, item.Spellchecker.IsCorrect("cherry"))
+			require.False(t, item.Spellchecker.IsCorrect("apple"))
+
+			// nothing has changed since the previous reload
+			result, err = r.Reload(context.Background())
+			require.NoError(t, err)
+			require.False(t, result.Changed())
+		})
+
+		t.Run(fmt.Sprintf("removed, lazy %v", lazy), func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			dropFile(t, dir, "code", "apple")
+
+			r, err := NewRegistry(context.Background(), dir, opts...)
+			require.NoError(t, err)
+			require.NoError(t, r.SetAlias("alias", "code"))
+
+			require.NoError(t, os.Remove(fullPath(dir, "code")))
+
+			result, err := r.Reload(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []string{"code"}, result.Removed)
+
+			_, err = r.GetItem("code")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			_, err = r.GetCodeByAlias("alias")
+			require.ErrorIs(t, err, ErrAliasNotFound)
+		})
+	}
+
+	t.Run("own saves are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+		require.NoError(t, r.SetAlias("alias", "code"))
+		require.NoError(t, r.Save("code"))
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc")
+
+		result, err := r.Reload(context.Background())
+		require.NoError(t, err)
+		require.False(t, result.Changed())
+
+		// the words added after the save are kept
+		item, err = r.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("abc"))
+	})
+
+	t.Run("broken file", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		dropFile(t, dir, "code", "apple")
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(fullPath(dir, "code"), []byte("garbage"), 0644))
+		require.NoError(t, os.WriteFile(fullPath(dir, "new"), []byte("garbage"), 0644))
+
+		result, err := r.Reload(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.Errors, 2)
+		require.False(t, result.Changed())
+
+		// the dictionary in memory is kept, the new file is quarantined
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("apple"))
+
+		require.Len(t, r.Failed(), 1)
+		require.FileExists(t, path.Join(dir, quarantineDir, fileName("new")))
+	})
+
+	t.Run("failed file is retried", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		dropFile(t, dir, "code", "apple")
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		tmp := t.TempDir()
+		dropFile(t, tmp, "code", "banana")
+
+		data, err := os.ReadFile(fullPath(tmp, "code"))
+		require.NoError(t, err)
+
+		// the file is read while it is being written, its final content has the same size and modification time
+		modTime := time.Now().Add(time.Hour).Truncate(time.Second)
+		require.NoError(t, os.WriteFile(fullPath(dir, "code"), make([]byte, len(data)), 0644))
+		require.NoError(t, os.Chtimes(fullPath(dir, "code"), modTime, modTime))
+
+		result, err := r.Reload(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.Errors, 1)
+
+		require.NoError(t, os.WriteFile(fullPath(dir, "code"), data, 0644))
+		require.NoError(t, os.Chtimes(fullPath(dir, "code"), modTime, modTime))
+
+		result, err = r.Reload(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, result.Errors)
+		require.Equal(t, []string{"code"}, result.Replaced)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("banana"))
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		dropFile(t, dir, "code", "apple")
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		m := newMetadata()
+		m.Aliases["alias"] = "code"
+		m.Aliases["broken"] = "missing"
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path.Join(dir, "metadata"), encodeFile(data), 0644))
+
+		result, err := r.Reload(context.Background())
+		require.NoError(t, err)
+		require.True(t, result.Metadata)
+
+		code, err := r.GetCodeByAlias("alias")
+		require.NoError(t, err)
+		require.Equal(t, "code", code)
+		require.Equal(t, []string{"alias"}, r.metadata.InvertedAliases["code"])
+
+		// aliases pointing to missing dictionaries are removed
+		_, err = r.GetCodeByAlias("broken")
+		require.ErrorIs(t, err, ErrAliasNotFound)
+
+		result, err = r.Reload(context.Background())
+		require.NoError(t, err)
+		require.False(t, result.Changed())
+	})
+	t.Run("metadata with invalid splits", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		dropFile(t, dir, "a", "apple")
+		dropFile(t, dir, "b", "apple")
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+		require.NoError(t, r.SetAlias("alias", "a"))
+
+		m := newMetadata()
+		m.Aliases["alias"] = "a"
+		m.Splits["alias"] = []AliasTarget{{Dictionary: "a"}, {Dictionary: "b"}}
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path.Join(dir, "metadata"), encodeFile(data), 0644))
+
+		result, err := r.Reload(context.Background())
+		require.NoError(t, err)
+		require.False(t, result.Metadata)
+		require.Len(t, result.Errors, 1)
+		require.Contains(t, result.Errors[0], ErrInvalidAliasTargets.Error())
+		require.Empty(t, r.metadata.Splits)
+
+		// the file is read again by the next reload
+		result, err = r.Reload(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.Errors, 1)
+	})
+}
@@ -0,0 +1,178 @@
+package spellchecker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// instanceID makes the versions of the dictionaries differ between processes, the revisions start from zero after a restart
+var instanceID = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// ReplicationState describes the dictionaries and the aliases of the registry, followers compare it with the state they have received
+type ReplicationState struct {
+	Dictionaries map[string]string `json:"dictionaries"` // code => version, the version changes on each change of the dictionary
+	Metadata     Metadata          `json:"metadata"`
+}
+
+// ReplicationState returns the versions of the dictionaries and the metadata.
+// A version is an opaque string derived from the saved file and the unsaved changes, so loading, unloading
+// or restarting does not change it. A dictionary may get a new version without changes (e.g. when it is saved again).
+func (r *Registry) ReplicationState() (ReplicationState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := ReplicationState{
+		Dictionaries: make(map[string]string, len(r.items)+len(r.unloaded)),
+		Metadata:     r.metadata.clone(),
+	}
+
+	for code, item := range r.items {
+		result.Dictionaries[code] = r.doVersion(code, item)
+	}
+
+	var unknown bool
+
+	for code := range r.unloaded {
+		if sum, ok := r.checksum(code); ok {
+			result.Dictionaries[code] = fileVersion(sum)
+		} else {
+			unknown = true
+		}
+	}
+
+	if !unknown {
+		return result, nil
+	}
+
+	// the checksum of a legacy file is not read until the dictionary is loaded, the file does not change until then
+	files, err := r.findDictionaries()
+	if err != nil {
+		return ReplicationState{}, err
+	}
+
+	for _, f := range files {
+		code, _ := strings.CutSuffix(f.Name, extension)
+		if _, ok := r.unloaded[code]; ok && result.Dictionaries[code] == "" {
+			result.Dictionaries[code] = fmt.Sprintf("file.%d.%d", f.ModTime.UnixNano(), f.Size)
+		}
+	}
+
+	return result, nil
+}
+
+// doVersion returns the version of the loaded dictionary: the checksum of its file, the unsaved changes are added if there are any
+func (r *Registry) doVersion(code string, item RegistryItem) string {
+	sum, ok := r.checksum(code)
+	if !ok {
+		return item.version()
+	}
+
+	if r.cache.dirty(code, item.revision()) {
+		return fileVersion(sum) + "." + item.version()
+	}
+
+	return fileVersion(sum)
+}
+
+func fileVersion(sum uint32) string {
+	return fmt.Sprintf("crc.%08x", sum)
+}
+
+// WriteDictionary writes the file of the dictionary with its current state. The lock is released before streaming.
+func (r *Registry) WriteDictionary(code string, w io.Writer) error {
+	dir, err := os.MkdirTemp("", "spellchecker-replication-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := path.Join(dir, fileName(code))
+
+	if err := r.snapshotFile(code, filePath); err != nil {
+		return err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+func (r *Registry) snapshotFile(code string, filePath string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.doExists(code) {
+		return ErrNotFound
+	}
+
+	return r.doBackupFile(code, filePath)
+}
+
+// ReplaceDictionary adds the dictionary from the file written by WriteDictionary or replaces the existing one.
+// The file is decoded before the registry is changed, ErrCorrupted is returned if it cannot be decoded.
+func (r *Registry) ReplaceDictionary(code string, rd io.Reader) error {
+	if !validFileCode(code) {
+		return fmt.Errorf("%w: invalid code %q", ErrInvalidOptions, code)
+	}
+
+	dir, err := os.MkdirTemp("", "spellchecker-replication-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := r.readBackupFile(rd, path.Join(dir, fileName(code)))
+	if err != nil && !errors.Is(err, ErrCorrupted) && !errors.Is(err, ErrUnsupportedFormat) {
+		return fmt.Errorf("%w: %w", ErrCorrupted, err)
+	} else if err != nil {
+		return err
+	}
+
+	// a dictionary being loaded must not be inserted after the replacement
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.writeFile(fileName(code), d.path); err != nil {
+		return err
+	}
+
+	return r.doRestored(code, d)
+}
+
+// ReplaceMetadata replaces the aliases and their history, e.g. with the ones received from the replication leader
+func (r *Registry) ReplaceMetadata(m Metadata) error {
+	m = m.clone()
+	m.init()
+	m.rebuildInverted()
+
+	if err := m.checkSplits(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.metadata = m
+
+	return r.doSaveMetadata()
+}
+
+// version identifies the state of the dictionary
+func (r RegistryItem) version() string {
+	return fmt.Sprintf("%s.%d.%d", instanceID, r.id, r.revision())
+}
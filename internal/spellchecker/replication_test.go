@@ -0,0 +1,176 @@
+package spellchecker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_ReplicationState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loaded", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+		require.NoError(t, r.SetAlias("alias", "code"))
+
+		state, err := r.ReplicationState()
+		require.NoError(t, err)
+		require.Contains(t, state.Dictionaries, "code")
+		require.Equal(t, "code", state.Metadata.Aliases["alias"])
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc")
+
+		state2, err := r.ReplicationState()
+		require.NoError(t, err)
+		require.NotEqual(t, state.Dictionaries["code"], state2.Dictionaries["code"])
+	})
+
+	t.Run("unloaded", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		r, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+		require.NoError(t, r.SaveAll(context.Background()))
+
+		r2, err := NewRegistry(context.Background(), dir, WithLazyLoading(0))
+		require.NoError(t, err)
+
+		state, err := r2.ReplicationState()
+		require.NoError(t, err)
+		require.Contains(t, state.Dictionaries, "code")
+
+		state2, err := r2.ReplicationState()
+		require.NoError(t, err)
+		require.Equal(t, state.Dictionaries, state2.Dictionaries)
+
+		// the version depends on the saved content only, so it is kept after loading and reopening
+		item, err := r2.GetItem("code")
+		require.NoError(t, err)
+		defer item.Release()
+
+		state3, err := r2.ReplicationState()
+		require.NoError(t, err)
+		require.Equal(t, state.Dictionaries, state3.Dictionaries)
+
+		r3, err := NewRegistry(context.Background(), dir)
+		require.NoError(t, err)
+
+		state4, err := r3.ReplicationState()
+		require.NoError(t, err)
+		require.Equal(t, state.Dictionaries, state4.Dictionaries)
+
+		item.AddWeight(1, "abc")
+
+		state5, err := r2.ReplicationState()
+		require.NoError(t, err)
+		require.NotEqual(t, state.Dictionaries["code"], state5.Dictionaries["code"])
+	})
+}
+
+func Test_Registry_ReplaceDictionary(t *testing.T) {
+	t.Parallel()
+
+	for _, lazy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lazy %v", lazy), func(t *testing.T) {
+			t.Parallel()
+
+			leader, err := NewRegistry(context.Background(), t.TempDir())
+			require.NoError(t, err)
+
+			_, err = leader.Add("code", Options{Alphabet: "abc"})
+			require.NoError(t, err)
+
+			item, err := leader.GetItem("code")
+			require.NoError(t, err)
+			item.AddWeight(1, "abc")
+
+			var opts []RegistryOption
+			if lazy {
+				opts = append(opts, WithLazyLoading(0))
+			}
+
+			dir := t.TempDir()
+
+			follower, err := NewRegistry(context.Background(), dir, opts...)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, leader.WriteDictionary("code", &buf))
+			require.NoError(t, follower.ReplaceDictionary("code", &buf))
+
+			item, err = follower.GetItem("code")
+			require.NoError(t, err)
+			require.True(t, item.Spellchecker.IsCorrect("abc"))
+
+			require.FileExists(t, fullPath(dir, "code"))
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		require.ErrorIs(t, r.WriteDictionary("code", &bytes.Buffer{}), ErrNotFound)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewRegistry(context.Background(), t.TempDir())
+		require.NoError(t, err)
+
+		require.Error(t, r.ReplaceDictionary("code", bytes.NewBufferString("garbage")))
+		require.Error(t, r.ReplaceDictionary("../code", bytes.NewBufferString("garbage")))
+		require.Empty(t, r.List())
+	})
+}
+
+func Test_Registry_ReplaceMetadata(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+
+	_, err = r.Add("code", Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	require.NoError(t, r.ReplaceMetadata(Metadata{Aliases: map[string]string{"alias": "code"}}))
+
+	code, err := r.GetCodeByAlias("alias")
+	require.NoError(t, err)
+	require.Equal(t, "code", code)
+	require.Equal(t, []string{"alias"}, r.metadata.InvertedAliases["code"])
+
+	require.NoError(t, r.Save("code"))
+	r2 := reopen(t, r)
+
+	code, err = r2.GetCodeByAlias("alias")
+	require.NoError(t, err)
+	require.Equal(t, "code", code)
+
+	// the splits are validated as the ones set by SetAliasTargets
+	err = r.ReplaceMetadata(Metadata{
+		Aliases: map[string]string{"split": "code"},
+		Splits:  map[string][]AliasTarget{"split": {{Dictionary: "code"}, {Dictionary: "alias"}}},
+	})
+	require.ErrorIs(t, err, ErrInvalidAliasTargets)
+	require.NotContains(t, r.metadata.Aliases, "split")
+}
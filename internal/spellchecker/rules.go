@@ -0,0 +1,289 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidRule           = fmt.Errorf("invalid rule")
+	ErrRuleNotFound          = fmt.Errorf("rule not found")
+	ErrForbiddenWordNotFound = fmt.Errorf("forbidden word not found")
+)
+
+// Rule replaces a word or a phrase regardless of the spellchecker suggestions
+type Rule struct {
+	ID          string `json:"id"`
+	Pattern     string `json:"pattern"`     // a word/phrase or a regular expression
+	Regexp      bool   `json:"regexp"`      // the pattern is a regular expression
+	Replacement string `json:"replacement"` // may contain $1-like references to regexp groups
+}
+
+// RuleMatch is a match of a rule in a text. Start and End are byte offsets in the text.
+type RuleMatch struct {
+	RuleID      string
+	Start       int
+	End         int
+	Replacement string
+}
+
+type compiledRule struct {
+	Rule
+	re *regexp.Regexp
+}
+
+// Rules contains static correction rules and forbidden words of a dictionary
+type Rules struct {
+	mu sync.RWMutex
+
+	rules     map[string]compiledRule
+	forbidden map[string]struct{}
+	changes   uint64
+}
+
+func NewRules() *Rules {
+	return &Rules{
+		rules:     make(map[string]compiledRule),
+		forbidden: make(map[string]struct{}),
+	}
+}
+
+func compileRule(rule Rule) (compiledRule, error) {
+	if rule.ID == "" || rule.Pattern == "" {
+		return compiledRule{}, fmt.Errorf("%w: id and pattern must not be empty", ErrInvalidRule)
+	}
+
+	pattern := rule.Pattern
+	if !rule.Regexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return compiledRule{}, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+	}
+
+	return compiledRule{Rule: rule, re: re}, nil
+}
+
+func (r *Rules) SetRule(rule Rule) error {
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules[rule.ID] = compiled
+	r.changes++
+
+	return nil
+}
+
+func (r *Rules) DeleteRule(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rules[id]; !ok {
+		return ErrRuleNotFound
+	}
+
+	delete(r.rules, id)
+	r.changes++
+
+	return nil
+}
+
+func (r *Rules) revision() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.changes
+}
+
+func (r *Rules) ListRules() []Rule {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Rule, 0, len(r.rules))
+	for _, v := range r.rules {
+		result = append(result, v.Rule)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result
+}
+
+func (r *Rules) Forbid(word string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.forbidden[word] = struct{}{}
+	r.changes++
+}
+
+func (r *Rules) Allow(word string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.forbidden[word]; !ok {
+		return ErrForbiddenWordNotFound
+	}
+
+	delete(r.forbidden, word)
+	r.changes++
+
+	return nil
+}
+
+func (r *Rules) ListForbidden() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]string, 0, len(r.forbidden))
+	for w := range r.forbidden {
+		result = append(result, w)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func (r *Rules) IsForbidden(word string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.forbidden[word]
+
+	return ok
+}
+
+// Match applies the rules to the text. Matches must start and end at word boundaries.
+// Overlapping matches are resolved in favor of the leftmost (and then the longest) one.
+func (r *Rules) Match(text string) []RuleMatch {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var all []RuleMatch
+
+	for _, rule := range r.rules {
+		for _, m := range rule.re.FindAllStringSubmatchIndex(text, -1) {
+			if m[0] == m[1] || !isBoundary(text, m[0]) || !isBoundary(text, m[1]) {
+				continue
+			}
+
+			all = append(all, RuleMatch{
+				RuleID:      rule.ID,
+				Start:       m[0],
+				End:         m[1],
+				Replacement: string(rule.re.ExpandString(nil, rule.Replacement, text, m)),
+			})
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Start != all[j].Start {
+			return all[i].Start < all[j].Start
+		}
+
+		if all[i].End != all[j].End {
+			return all[i].End > all[j].End
+		}
+
+		return all[i].RuleID < all[j].RuleID
+	})
+
+	result := make([]RuleMatch, 0, len(all))
+	end := 0
+
+	for _, m := range all {
+		if m.Start < end {
+			continue
+		}
+
+		result = append(result, m)
+		end = m.End
+	}
+
+	return result
+}
+
+// isBoundary checks that the position is not inside a word
+func isBoundary(text string, pos int) bool {
+	if pos == 0 || pos == len(text) {
+		return true
+	}
+
+	before, _ := utf8.DecodeLastRuneInString(text[:pos])
+	after, _ := utf8.DecodeRuneInString(text[pos:])
+
+	return !isWordRune(before) || !isWordRune(after)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type rulesData struct {
+	Rules     []Rule   `json:"rules"`
+	Forbidden []string `json:"forbidden"`
+}
+
+func (r *Rules) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rulesData{
+		Rules:     r.ListRules(),
+		Forbidden: r.ListForbidden(),
+	})
+}
+
+func (r *Rules) UnmarshalJSON(data []byte) error {
+	var value rulesData
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = make(map[string]compiledRule, len(value.Rules))
+	for _, rule := range value.Rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return err
+		}
+
+		r.rules[rule.ID] = compiled
+	}
+
+	r.forbidden = make(map[string]struct{}, len(value.Forbidden))
+	for _, w := range value.Forbidden {
+		r.forbidden[w] = struct{}{}
+	}
+
+	return nil
+}
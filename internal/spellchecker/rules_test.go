@@ -0,0 +1,130 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Rules_SetRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr error
+	}{
+		{name: "exact", rule: Rule{ID: "alot", Pattern: "alot", Replacement: "a lot"}},
+		{name: "regexp", rule: Rule{ID: "re", Pattern: `colou?r`, Regexp: true, Replacement: "color"}},
+		{name: "empty id", rule: Rule{Pattern: "alot"}, wantErr: ErrInvalidRule},
+		{name: "empty pattern", rule: Rule{ID: "alot"}, wantErr: ErrInvalidRule},
+		{name: "invalid regexp", rule: Rule{ID: "re", Pattern: "(", Regexp: true}, wantErr: ErrInvalidRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewRules().SetRule(tt.rule)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Rules_Match(t *testing.T) {
+	t.Parallel()
+
+	r := NewRules()
+	require.NoError(t, r.SetRule(Rule{ID: "alot", Pattern: "alot", Replacement: "a lot"}))
+	require.NoError(t, r.SetRule(Rule{ID: "brand", Pattern: "F1 Monkey", Replacement: "f1monkey"}))
+	require.NoError(t, r.SetRule(Rule{ID: "monkey", Pattern: "Monkey business", Replacement: "nonsense"}))
+	require.NoError(t, r.SetRule(Rule{ID: "colour", Pattern: `colou?r(s?)`, Regexp: true, Replacement: "hue$1"}))
+
+	tests := []struct {
+		name  string
+		input string
+		want  []RuleMatch
+	}{
+		{
+			name:  "exact",
+			input: "thanks alot",
+			want:  []RuleMatch{{RuleID: "alot", Start: 7, End: 11, Replacement: "a lot"}},
+		},
+		{
+			name:  "not at word boundary",
+			input: "zalot alots",
+			want:  []RuleMatch{},
+		},
+		{
+			name:  "multi-token",
+			input: "by F1 Monkey",
+			want:  []RuleMatch{{RuleID: "brand", Start: 3, End: 12, Replacement: "f1monkey"}},
+		},
+		{
+			name:  "overlapping matches",
+			input: "F1 Monkey business",
+			want:  []RuleMatch{{RuleID: "brand", Start: 0, End: 9, Replacement: "f1monkey"}},
+		},
+		{
+			name:  "regexp with groups",
+			input: "colours and color",
+			want: []RuleMatch{
+				{RuleID: "colour", Start: 0, End: 7, Replacement: "hues"},
+				{RuleID: "colour", Start: 12, End: 17, Replacement: "hue"},
+			},
+		},
+		{
+			name:  "unicode boundaries",
+			input: "ъalot alot",
+			want:  []RuleMatch{{RuleID: "alot", Start: 7, End: 11, Replacement: "a lot"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, r.Match(tt.input))
+		})
+	}
+}
+
+func Test_Rules_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	r := NewRules()
+
+	r.Forbid("irregardless")
+	require.True(t, r.IsForbidden("irregardless"))
+	require.Equal(t, []string{"irregardless"}, r.ListForbidden())
+
+	require.NoError(t, r.Allow("irregardless"))
+	require.False(t, r.IsForbidden("irregardless"))
+	require.ErrorIs(t, r.Allow("irregardless"), ErrForbiddenWordNotFound)
+}
+
+func Test_Rules_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	r := NewRules()
+	require.NoError(t, r.SetRule(Rule{ID: "alot", Pattern: "alot", Replacement: "a lot"}))
+	r.Forbid("irregardless")
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+
+	r2 := NewRules()
+	require.NoError(t, json.Unmarshal(data, r2))
+	require.Equal(t, r.ListRules(), r2.ListRules())
+	require.True(t, r2.IsForbidden("irregardless"))
+	require.Len(t, r2.Match("alot"), 1)
+
+	var nilRules *Rules
+	require.Nil(t, nilRules.Match("alot"))
+	require.False(t, nilRules.IsForbidden("alot"))
+}
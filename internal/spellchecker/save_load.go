@@ -3,12 +3,15 @@ package spellchecker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"path"
 	"time"
 
 	"github.com/f1monkey/spellchecker-web/internal/logger"
+	"github.com/f1monkey/spellchecker-web/internal/storage"
 )
 
 func (r *Registry) AutoSave(ctx context.Context, interval time.Duration) {
@@ -42,7 +45,7 @@ func (r *Registry) SaveAll(ctx context.Context) error {
 	}
 
 	for code := range r.items {
-		if err := r.doSave(code); err != nil {
+		if err := r.doSave(code, false); err != nil {
 			return fmt.Errorf("dictionary %q save: %w", code, err)
 		}
 
@@ -56,94 +59,195 @@ func (r *Registry) Save(code string) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.doSave(code)
+	if _, ok := r.unloaded[code]; ok {
+		if r.versions <= 0 {
+			return nil
+		}
+
+		return r.doSnapshot(code)
+	}
+
+	return r.doSave(code, true)
 }
 
-func (r *Registry) doSave(code string) error {
+// doSave writes the dictionary to disk. If snapshot is true and versioning is enabled, a copy is kept as a new version.
+func (r *Registry) doSave(code string, snapshot bool) error {
 	item, ok := r.items[code]
 	if !ok {
 		return ErrNotFound
 	}
 
-	data, err := json.Marshal(&item)
+	return r.saveItem(code, item, snapshot)
+}
+
+// saveItem writes the dictionary to disk, it does not access the registry maps, so the lock is not required
+func (r *Registry) saveItem(code string, item RegistryItem, snapshot bool) error {
+	// changes made during writing make the dictionary dirty again and stay in the operations log
+	revision := item.revision()
+
+	var (
+		info   FileInfo
+		offset int64
+		err    error
+	)
+
+	if item.log == nil {
+		err = r.storage.Write(fileName(code), func(w storage.Writer) error {
+			var err error
+			info, err = writeItem(w, item, r.compression)
+
+			return err
+		})
+	} else {
+		info, offset, err = r.saveLogged(code, item)
+	}
+
 	if err != nil {
 		return err
 	}
 
-	dstPath := path.Join(r.dir, fileName(code))
+	if err := item.log.truncate(offset); err != nil {
+		return fmt.Errorf("operations log truncate: %w", err)
+	}
+
+	r.cache.saved(code, info.DecodedSize, revision)
+	r.setChecksum(code, info.Checksum)
+
+	if snapshot && r.versions > 0 {
+		return r.doSnapshot(code)
+	}
+
+	return nil
+}
 
-	tmpFile, err := os.CreateTemp(r.dir, fileName(code)+".tmp-*")
+// saveLogged writes the dictionary having the operations log to a local file while the log is locked, then copies the file to the storage.
+// Returns the offset of the log matching the saved content.
+func (r *Registry) saveLogged(code string, item RegistryItem) (FileInfo, int64, error) {
+	tmpFile, err := os.CreateTemp(r.walDir, code+".save-*")
 	if err != nil {
-		return err
+		return FileInfo{}, 0, err
 	}
-	tmpName := tmpFile.Name()
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	var info FileInfo
+
+	offset, err := item.log.snapshot(func() error {
+		var err error
+		info, err = writeItem(tmpFile, item, r.compression)
 
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpName)
 		return err
+	})
+	if err != nil {
+		return FileInfo{}, 0, err
+	}
+
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return FileInfo{}, 0, err
 	}
 
-	if err := tmpFile.Close(); err != nil {
-		os.Remove(tmpName)
+	err = r.storage.Write(fileName(code), func(w storage.Writer) error {
+		_, err := io.Copy(w, tmpFile)
+
 		return err
+	})
+
+	return info, offset, err
+}
+
+func (r *Registry) doLoad(code string) (RegistryItem, FileInfo, error) {
+	return r.readItem(fileName(code))
+}
+
+// readItem reads the dictionary from the file of the storage
+func (r *Registry) readItem(name string) (RegistryItem, FileInfo, error) {
+	f, err := r.storage.Open(name)
+	if err != nil {
+		return RegistryItem{}, FileInfo{}, err
 	}
+	defer f.Close()
 
-	return os.Rename(tmpName, dstPath)
+	return loadItem(f)
 }
 
-func (r *Registry) doLoad(code string) (RegistryItem, error) {
-	buf, err := os.ReadFile(fullPath(r.dir, code))
+// readOptions reads only the options of the dictionary file
+func (r *Registry) readOptions(code string) (Options, FileInfo, error) {
+	f, err := r.storage.Open(fileName(code))
 	if err != nil {
-		return RegistryItem{}, err
+		return Options{}, FileInfo{}, err
 	}
+	defer f.Close()
 
-	var item RegistryItem
+	return readOptions(f)
+}
 
-	if err := json.Unmarshal(buf, &item); err != nil {
-		return RegistryItem{}, err
+// copyFile copies the file within the storage
+func (r *Registry) copyFile(from string, to string) error {
+	f, err := r.storage.Open(from)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+
+	return r.storage.Write(to, func(w storage.Writer) error {
+		_, err := io.Copy(w, f)
 
-	return item, nil
+		return err
+	})
 }
 
 func (r *Registry) doLoadMetadata() (Metadata, error) {
-	data, err := os.ReadFile(path.Join(r.dir, metadataFile))
-	if os.IsNotExist(err) {
+	data, err := r.storage.GetMetadata()
+	if errors.Is(err, fs.ErrNotExist) {
 		return newMetadata(), nil
 	} else if err != nil {
 		return newMetadata(), err
 	}
 
-	var result Metadata
-
-	return result, json.Unmarshal(data, &result)
+	return decodeMetadata(data)
 }
 
-func (r *Registry) doSaveMetadata() error {
-	data, err := json.Marshal(r.metadata)
+// decodeMetadata decodes the content of the metadata file
+func decodeMetadata(data []byte) (Metadata, error) {
+	data, _, err := decodeFile(data)
 	if err != nil {
-		return err
+		return newMetadata(), err
 	}
 
-	dstPath := path.Join(r.dir, metadataFile)
+	result := newMetadata()
+	if err := json.Unmarshal(data, &result); err != nil {
+		return newMetadata(), err
+	}
 
-	tmpFile, err := os.CreateTemp(r.dir, metadataFile+".tmp-*")
-	if err != nil {
-		return err
+	result.init()
+
+	if err := result.checkSplits(); err != nil {
+		return newMetadata(), err
 	}
-	tmpName := tmpFile.Name()
 
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpName)
-		return err
+	return result, nil
+}
+
+// init creates the maps missing in the decoded metadata
+func (m *Metadata) init() {
+	if m.Aliases == nil {
+		m.Aliases = make(map[string]string)
+	}
+
+	if m.InvertedAliases == nil {
+		m.InvertedAliases = make(map[string][]string)
 	}
 
-	if err := tmpFile.Close(); err != nil {
-		os.Remove(tmpName)
+	if m.Splits == nil {
+		m.Splits = make(map[string][]AliasTarget)
+	}
+}
+
+func (r *Registry) doSaveMetadata() error {
+	data, err := json.Marshal(r.metadata)
+	if err != nil {
 		return err
 	}
 
-	return os.Rename(tmpName, dstPath)
+	return r.storage.PutMetadata(encodeFile(data))
 }
@@ -5,6 +5,7 @@ import (
 	"path"
 	"testing"
 
+	"github.com/f1monkey/spellchecker-web/internal/storage"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,6 +47,35 @@ func Test_Registry_Save(t *testing.T) {
 	})
 }
 
+func Test_Registry_Save_Phonetic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	code := "code"
+
+	r, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	_, err = r.Add(code, Options{Alphabet: "abcdefghijklmnopqrstuvwxyz", MaxErrors: 1, Phonetic: "metaphone"})
+	require.NoError(t, err)
+
+	item, err := r.GetItem(code)
+	require.NoError(t, err)
+
+	item.AddWeight(1, "phonetic")
+
+	err = r.Save(code)
+	require.NoError(t, err)
+
+	r2, err := NewRegistry(context.Background(), dir)
+	require.NoError(t, err)
+
+	item, err = r2.GetItem(code)
+	require.NoError(t, err)
+	require.NotNil(t, item.Phonetic)
+	require.Len(t, item.Phonetic.Find("fonetik", 5), 1)
+}
+
 func Test_Registry_SaveAll(t *testing.T) {
 	t.Parallel()
 
@@ -64,7 +94,7 @@ func Test_Registry_SaveAll(t *testing.T) {
 		err = r.SaveAll(context.Background())
 		require.NoError(t, err)
 		require.FileExists(t, path.Join(dir, fileName(code)))
-		require.FileExists(t, path.Join(dir, metadataFile))
+		require.FileExists(t, path.Join(dir, storage.MetadataFile))
 
 		r2, err := NewRegistry(context.Background(), dir)
 		require.NoError(t, err)
@@ -0,0 +1,260 @@
+package spellchecker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const versionsDir = "versions"
+
+var (
+	ErrVersionNotFound = fmt.Errorf("version not found")
+	ErrWordsNotKept    = fmt.Errorf("the version is saved without the words weights")
+	ErrNoVersions      = fmt.Errorf("versioning is disabled")
+)
+
+type Version struct {
+	ID        uint64
+	CreatedAt time.Time
+	Size      int64
+}
+
+type WordChange struct {
+	Word string
+	From uint
+	To   uint
+}
+
+// Diff contains changes of the words between two versions of a dictionary
+type Diff struct {
+	Added      []WordChange
+	Removed    []WordChange
+	Reweighted []WordChange
+}
+
+// ListVersions returns saved versions of the dictionary, the newest first
+func (r *Registry) ListVersions(code string) ([]Version, error) {
+	r.mu.RLock()
+	ok := r.doExists(code)
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	r.versionsMu.Lock()
+	defer r.versionsMu.Unlock()
+
+	return r.doListVersions(code)
+}
+
+// Diff compares two versions of the dictionary. If to is 0, the version is compared with the current state of the dictionary.
+func (r *Registry) Diff(code string, from uint64, to uint64) (Diff, error) {
+	current, err := r.item(code)
+	if err != nil {
+		return Diff{}, err
+	}
+	defer current.Release()
+
+	fromItem, err := r.loadVersion(code, from)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	toItem := current
+	if to > 0 {
+		toItem, err = r.loadVersion(code, to)
+		if err != nil {
+			return Diff{}, err
+		}
+	}
+
+	if fromItem.Words == nil || toItem.Words == nil {
+		return Diff{}, ErrWordsNotKept
+	}
+
+	return diff(fromItem.Words.Counts(), toItem.Words.Counts()), nil
+}
+
+// Rollback replaces the dictionary with the saved version.
+// The current state of the dictionary is saved as a new version before that, so versioning must be enabled.
+func (r *Registry) Rollback(code string, id uint64) error {
+	if r.versions <= 0 {
+		return ErrNoVersions
+	}
+
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.doItem(code); err != nil {
+		return err
+	}
+
+	item, err := r.loadVersion(code, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.doSave(code, true); err != nil {
+		return err
+	}
+
+	// the replaced dictionary may still be used by requests, its log is closed, so their changes are not replayed on top of the version
+	if err := r.doRemoveLog(code); err != nil {
+		return err
+	}
+
+	r.items[code] = item
+
+	if err := r.doSave(code, false); err != nil {
+		return err
+	}
+
+	r.doOpenLog(code)
+
+	return nil
+}
+
+func (r *Registry) loadVersion(code string, id uint64) (RegistryItem, error) {
+	item, _, err := r.readItem(path.Join(versionsPath(code), versionFileName(id)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return RegistryItem{}, fmt.Errorf("%w: %d", ErrVersionNotFound, id)
+	}
+
+	return item, err
+}
+
+// doSnapshot copies the saved file as a new version of the dictionary and removes the outdated versions
+func (r *Registry) doSnapshot(code string) error {
+	r.versionsMu.Lock()
+	defer r.versionsMu.Unlock()
+
+	dir := versionsPath(code)
+
+	versions, err := r.doListVersions(code)
+	if err != nil {
+		return err
+	}
+
+	var id uint64 = 1
+	if len(versions) > 0 {
+		id = versions[0].ID + 1
+	}
+
+	if err := r.copyFile(fileName(code), path.Join(dir, versionFileName(id))); err != nil {
+		return err
+	}
+
+	// the new version is not in the list yet
+	for i := r.versions - 1; i < len(versions); i++ {
+		if err := r.storage.Delete(path.Join(dir, versionFileName(versions[i].ID))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) doListVersions(code string) ([]Version, error) {
+	files, err := r.storage.List(versionsPath(code))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Version, 0, len(files))
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name, extension)
+		if !ok {
+			continue
+		}
+
+		id, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, Version{
+			ID:        id,
+			CreatedAt: f.ModTime,
+			Size:      f.Size,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b Version) int {
+		if a.ID > b.ID {
+			return -1
+		} else if a.ID < b.ID {
+			return 1
+		}
+
+		return 0
+	})
+
+	return result, nil
+}
+
+// doDeleteVersions removes all the versions of the dictionary
+func (r *Registry) doDeleteVersions(code string) error {
+	r.versionsMu.Lock()
+	defer r.versionsMu.Unlock()
+
+	versions, err := r.doListVersions(code)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range versions {
+		if err := r.storage.Delete(path.Join(versionsPath(code), versionFileName(v.ID))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func diff(from map[string]uint, to map[string]uint) Diff {
+	result := Diff{
+		Added:      make([]WordChange, 0),
+		Removed:    make([]WordChange, 0),
+		Reweighted: make([]WordChange, 0),
+	}
+
+	for w, cnt := range to {
+		prev, ok := from[w]
+		if !ok {
+			result.Added = append(result.Added, WordChange{Word: w, To: cnt})
+		} else if prev != cnt {
+			result.Reweighted = append(result.Reweighted, WordChange{Word: w, From: prev, To: cnt})
+		}
+	}
+
+	for w, cnt := range from {
+		if _, ok := to[w]; !ok {
+			result.Removed = append(result.Removed, WordChange{Word: w, From: cnt})
+		}
+	}
+
+	byWord := func(a, b WordChange) int { return strings.Compare(a.Word, b.Word) }
+	slices.SortFunc(result.Added, byWord)
+	slices.SortFunc(result.Removed, byWord)
+	slices.SortFunc(result.Reweighted, byWord)
+
+	return result
+}
+
+func versionFileName(id uint64) string {
+	return strconv.FormatUint(id, 10) + extension
+}
+
+func versionsPath(code string) string {
+	return path.Join(versionsDir, code)
+}
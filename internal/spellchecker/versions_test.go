@@ -0,0 +1,162 @@
+package spellchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_Versions(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T, versions int) *Registry {
+		r, err := NewRegistry(context.Background(), t.TempDir(), WithVersions(versions))
+		require.NoError(t, err)
+
+		_, err = r.Add("code", Options{Alphabet: "abcdefghijklmnopqrstuvwxyz"})
+		require.NoError(t, err)
+
+		return r
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 0)
+		require.NoError(t, r.Save("code"))
+
+		versions, err := r.ListVersions("code")
+		require.NoError(t, err)
+		require.Empty(t, versions)
+
+		// the state before a rollback could not be kept
+		require.ErrorIs(t, r.Rollback("code", 1), ErrNoVersions)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 2)
+
+		_, err := r.ListVersions("qwerty")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = r.Diff("code", 1, 0)
+		require.ErrorIs(t, err, ErrVersionNotFound)
+
+		err = r.Rollback("code", 1)
+		require.ErrorIs(t, err, ErrVersionNotFound)
+	})
+
+	t.Run("auto save does not create versions", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 2)
+		require.NoError(t, r.SaveAll(context.Background()))
+
+		versions, err := r.ListVersions("code")
+		require.NoError(t, err)
+		require.Empty(t, versions)
+	})
+
+	t.Run("keeps last versions", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 2)
+		for range 3 {
+			require.NoError(t, r.Save("code"))
+		}
+
+		versions, err := r.ListVersions("code")
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, uint64(3), versions[0].ID)
+		require.Equal(t, uint64(2), versions[1].ID)
+		require.NotZero(t, versions[0].Size)
+	})
+
+	t.Run("diff of a version without words", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 5)
+
+		// dictionaries saved before the words were kept
+		item := r.items["code"]
+		item.Words = nil
+		r.items["code"] = item
+
+		require.NoError(t, r.Save("code"))
+
+		_, err := r.Diff("code", 1, 0)
+		require.ErrorIs(t, err, ErrWordsNotKept)
+	})
+
+	t.Run("diff and rollback", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 5)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		item.AddWeight(1, "hello", "world")
+		require.NoError(t, r.Save("code"))
+
+		item.AddWeight(2, "hello", "weapon")
+		require.NoError(t, r.Save("code"))
+
+		d, err := r.Diff("code", 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, Diff{
+			Added:      []WordChange{{Word: "weapon", To: 2}},
+			Removed:    []WordChange{},
+			Reweighted: []WordChange{{Word: "hello", From: 1, To: 3}},
+		}, d)
+
+		item.AddWeight(1, "knight")
+
+		d, err = r.Diff("code", 2, 0)
+		require.NoError(t, err)
+		require.Equal(t, []WordChange{{Word: "knight", To: 1}}, d.Added)
+
+		require.NoError(t, r.Rollback("code", 1))
+
+		restored, err := r.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, restored.Spellchecker.IsCorrect("hello"))
+		require.False(t, restored.Spellchecker.IsCorrect("weapon"))
+		require.False(t, restored.Spellchecker.IsCorrect("knight"))
+
+		// the state before the rollback is kept as a new version
+		versions, err := r.ListVersions("code")
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+
+		d, err = r.Diff("code", versions[0].ID, 0)
+		require.NoError(t, err)
+		require.Equal(t, []WordChange{{Word: "knight", From: 1}, {Word: "weapon", From: 2}}, d.Removed)
+
+		// the restored version is saved to disk
+		loaded := reopen(t, r)
+
+		loadedItem, err := loaded.GetItem("code")
+		require.NoError(t, err)
+		require.False(t, loadedItem.Spellchecker.IsCorrect("weapon"))
+	})
+
+	t.Run("delete removes versions", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRegistry(t, 2)
+		require.NoError(t, r.Save("code"))
+		require.NoError(t, r.Delete("code", false))
+
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		versions, err := r.ListVersions("code")
+		require.NoError(t, err)
+		require.Empty(t, versions)
+	})
+}
@@ -0,0 +1,416 @@
+package spellchecker
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The operations log keeps the words added to a dictionary since its last save, so they survive a crash
+// between auto-saves. The log is a local file of records:
+//
+//	payload length (uint32) | CRC-32C of the payload (uint32) | payload (JSON)
+//
+// The log is replayed on top of the saved dictionary when the dictionary is loaded and is truncated after each save.
+// A save writes the dictionary to a local file while the log is locked and only then uploads it, so the records kept
+// after the save are exactly the ones missing in the file and no record is applied twice on replay.
+// A torn record at the end of the log (the process was killed while writing it) is discarded on replay.
+const (
+	walExtension        = ".log"
+	walRecordHeaderSize = 8
+	walSyncInterval     = time.Second
+
+	// maxWALRecordSize protects from allocating memory for the length read from a corrupted log
+	maxWALRecordSize = 64 << 20
+)
+
+// WALSync is the fsync policy of the operations log
+type WALSync string
+
+const (
+	WALSyncAlways   WALSync = "always"   // each record is synced before the request completes
+	WALSyncInterval WALSync = "interval" // the logs are synced once a second
+	WALSyncNever    WALSync = "never"    // syncing is left to the OS, the records survive a crash of the process but not of the OS
+)
+
+func ParseWALSync(s string) (WALSync, error) {
+	switch v := WALSync(s); v {
+	case WALSyncAlways, WALSyncInterval, WALSyncNever:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown fsync policy %q", s)
+	}
+}
+
+// WithWAL enables the operations log of the dictionaries in the local directory.
+// The directory must not be shared between instances.
+func WithWAL(dir string, sync WALSync) RegistryOption {
+	return func(r *Registry) {
+		r.walDir = dir
+		r.walSync = sync
+	}
+}
+
+type walRecord struct {
+	Weight uint     `json:"weight"`
+	Words  []string `json:"words"`
+}
+
+// walLog is the operations log of a dictionary
+type walLog struct {
+	mu sync.Mutex
+
+	path   string
+	policy WALSync
+	file   *os.File
+	size   int64
+	dirty  bool  // written since the last fsync
+	closed bool  // the dictionary is unloaded or replaced, the records of its copies still in use are dropped
+	broken error // the log file could not be reopened after a truncate, the records are dropped until the next save reopens it
+	log    *slog.Logger
+}
+
+func (r *Registry) walPath(code string) string {
+	return filepath.Join(r.walDir, code+walExtension)
+}
+
+// syncLogs syncs the logs of the loaded dictionaries periodically (WALSyncInterval policy)
+func (r *Registry) syncLogs(ctx context.Context) {
+	ticker := time.NewTicker(walSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.RLock()
+			for code, item := range r.items {
+				if err := item.log.flush(); err != nil {
+					r.log.Error("registry: operations log sync error", "dictionary", code, "error", err)
+				}
+			}
+			r.mu.RUnlock()
+		}
+	}
+}
+
+// doOpenLog replays the operations log of the loaded dictionary and attaches the log to it, so the following changes are written to the log.
+// Errors are logged: the dictionary is used without the log, the log file is kept as is.
+func (r *Registry) doOpenLog(code string) {
+	item, ok := r.items[code]
+	if !ok || r.walDir == "" || item.log != nil {
+		return
+	}
+
+	l, replayed, err := openLog(r.walPath(code), r.walSync, item, r.log.With("dictionary", code))
+	if err != nil {
+		r.log.Error("registry: operations log open error", "dictionary", code, "error", err)
+		return
+	}
+
+	if replayed > 0 {
+		r.log.Info("registry: operations log replayed", "dictionary", code, "records", replayed)
+	}
+
+	item.log = l
+	r.items[code] = item
+}
+
+// doCloseLog detaches the operations log from the dictionary and closes it
+func (r *Registry) doCloseLog(code string) {
+	item, ok := r.items[code]
+	if !ok || item.log == nil {
+		return
+	}
+
+	if err := item.log.close(); err != nil {
+		r.log.Error("registry: operations log close error", "dictionary", code, "error", err)
+	}
+
+	item.log = nil
+	r.items[code] = item
+}
+
+// doRemoveLog removes the operations log of the dictionary, e.g. when the dictionary file is replaced or removed
+func (r *Registry) doRemoveLog(code string) error {
+	if r.walDir == "" {
+		return nil
+	}
+
+	r.doCloseLog(code)
+
+	if err := os.Remove(r.walPath(code)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// openLog opens (or creates) the log and applies its records to the dictionary. Returns the number of replayed records.
+func openLog(filePath string, policy WALSync, item RegistryItem, log *slog.Logger) (*walLog, int, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, 0, err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	replayed, size, err := replayLog(f, item)
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	if size < stat.Size() {
+		log.Warn("registry: torn operations log record discarded", "offset", size, "size", stat.Size())
+
+		if err := f.Truncate(size); err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+	}
+
+	return &walLog{path: filePath, policy: policy, file: f, size: size, log: log}, replayed, nil
+}
+
+// replayLog applies the records to the dictionary. Returns the number of records and the size of the valid part of the log.
+func replayLog(r io.Reader, item RegistryItem) (int, int64, error) {
+	var (
+		count  int
+		offset int64
+		header [walRecordHeaderSize]byte
+	)
+
+	for {
+		if _, err := io.ReadFull(r, header[:]); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return count, offset, nil
+		} else if err != nil {
+			return count, offset, err
+		}
+
+		size := binary.LittleEndian.Uint32(header[0:4])
+		if size > maxWALRecordSize {
+			return count, offset, nil
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return count, offset, nil
+		} else if err != nil {
+			return count, offset, err
+		}
+
+		if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:8]) {
+			return count, offset, nil
+		}
+
+		var record walRecord
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return count, offset, nil
+		}
+
+		item.AddWeight(record.Weight, record.Words...)
+
+		count++
+		offset += walRecordHeaderSize + int64(size)
+	}
+}
+
+// doAppend writes the record to the log, the caller must hold the mutex.
+// A failed write is rolled back, so the log does not end with a torn record.
+func (l *walLog) doAppend(record walRecord) {
+	if l.closed {
+		l.log.Warn("registry: operations log record dropped, the log is closed")
+		return
+	}
+
+	if l.broken != nil {
+		l.log.Error("registry: operations log record dropped, the log is broken", "error", l.broken)
+		return
+	}
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		l.log.Error("registry: operations log write error", "error", err)
+		return
+	}
+
+	buf := make([]byte, walRecordHeaderSize, walRecordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, crcTable))
+	buf = append(buf, payload...)
+
+	if _, err := l.file.Write(buf); err != nil {
+		l.log.Error("registry: operations log write error", "error", err)
+		_ = l.file.Truncate(l.size)
+
+		return
+	}
+
+	l.size += int64(len(buf))
+
+	if l.policy != WALSyncAlways {
+		l.dirty = true
+		return
+	}
+
+	if err := l.file.Sync(); err != nil {
+		l.log.Error("registry: operations log sync error", "error", err)
+	}
+}
+
+// snapshot runs the function while no record can be written and returns the size of the log.
+// All the records before the offset are applied to the dictionary and none after it, so the function sees the dictionary as of the offset.
+func (l *walLog) snapshot(fn func() error) (int64, error) {
+	if l == nil {
+		return 0, fn()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.size, fn()
+}
+
+// truncate removes the records before the offset, the dictionary containing them is saved.
+// The records written during the save are kept, they are replayed again if the process crashes before the next save.
+// A broken log is reopened first: no records are written to it, so the saved dictionary contains all of them.
+func (l *walLog) truncate(offset int64) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.broken != nil {
+		f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return errors.Join(l.broken, err)
+		}
+
+		l.file = f
+		l.broken = nil
+	}
+
+	if offset >= l.size {
+		if err := l.file.Truncate(0); err != nil {
+			return err
+		}
+
+		l.size = 0
+		l.dirty = false
+
+		return l.file.Sync()
+	}
+
+	tail := make([]byte, l.size-offset)
+	if _, err := l.file.ReadAt(tail, offset); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmpFile.Name()
+
+	_, err = tmpFile.Write(tail)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+
+	if err := errors.Join(err, tmpFile.Close()); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, l.path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	// the open file is the removed one now, the records appended to it would be lost
+	_ = l.file.Close()
+	l.size = int64(len(tail))
+	l.dirty = false
+
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		l.broken = fmt.Errorf("reopen: %w", err)
+		return l.broken
+	}
+
+	l.file = f
+
+	return syncDir(filepath.Dir(l.path))
+}
+
+// flush syncs the records written since the last sync
+func (l *walLog) flush() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.broken != nil {
+		return l.broken
+	}
+
+	if !l.dirty {
+		return nil
+	}
+
+	l.dirty = false
+
+	return l.file.Sync()
+}
+
+func (l *walLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+
+	if l.broken != nil {
+		return nil
+	}
+
+	var err error
+	if l.dirty {
+		err = l.file.Sync()
+	}
+
+	return errors.Join(err, l.file.Close())
+}
+
+// syncDir makes the rename of a file in the directory durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -0,0 +1,336 @@
+package spellchecker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newWALRegistry(t *testing.T, dir string, walDir string, opts ...RegistryOption) *Registry {
+	r, err := NewRegistry(context.Background(), dir, append([]RegistryOption{WithWAL(walDir, WALSyncAlways)}, opts...)...)
+	require.NoError(t, err)
+
+	return r
+}
+
+func Test_Registry_WAL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replay after crash", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir)
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+		require.FileExists(t, path.Join(dir, fileName("code")))
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc", "cab")
+		item.AddWeight(2, "bca")
+
+		// the registry is not saved, as if the process was killed
+		r2 := newWALRegistry(t, dir, walDir)
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("abc"))
+		require.True(t, item.Spellchecker.IsCorrect("cab"))
+		require.True(t, item.Spellchecker.IsCorrect("bca"))
+		require.True(t, r2.cache.dirty("code", item.revision()))
+	})
+
+	t.Run("truncated after save", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir)
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc")
+
+		stat, err := os.Stat(path.Join(walDir, "code"+walExtension))
+		require.NoError(t, err)
+		require.NotZero(t, stat.Size())
+
+		require.NoError(t, r.SaveAll(context.Background()))
+
+		stat, err = os.Stat(path.Join(walDir, "code"+walExtension))
+		require.NoError(t, err)
+		require.Zero(t, stat.Size())
+
+		item.AddWeight(1, "cab")
+
+		r2 := newWALRegistry(t, dir, walDir)
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("abc"))
+		require.True(t, item.Spellchecker.IsCorrect("cab"))
+	})
+
+	t.Run("concurrent writes are replayed once", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir)
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+
+		const n = 200
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			for i := 0; i < n; i++ {
+				item.AddWeight(1, "abc")
+			}
+		}()
+
+	save:
+		for {
+			select {
+			case <-done:
+				break save
+			default:
+				require.NoError(t, r.Save("code"))
+			}
+		}
+
+		// the registry is not saved after the last writes, as if the process was killed
+		r2 := newWALRegistry(t, dir, walDir)
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+		require.Equal(t, map[string]uint{"abc": n}, item.Words.Counts())
+	})
+
+	t.Run("torn record", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir)
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc")
+
+		logPath := path.Join(walDir, "code"+walExtension)
+		stat, err := os.Stat(logPath)
+		require.NoError(t, err)
+
+		f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+		require.NoError(t, err)
+		_, err = f.Write([]byte{100, 0, 0, 0, 1, 2, 3})
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		r2 := newWALRegistry(t, dir, walDir)
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("abc"))
+
+		stat2, err := os.Stat(logPath)
+		require.NoError(t, err)
+		require.Equal(t, stat.Size(), stat2.Size())
+	})
+
+	t.Run("lazy loading", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir)
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc")
+
+		r2 := newWALRegistry(t, dir, walDir, WithLazyLoading(0))
+		require.Contains(t, r2.unloaded, "code")
+
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+		require.True(t, item.Spellchecker.IsCorrect("abc"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir)
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "abc")
+
+		require.NoError(t, r.Delete("code", false))
+		require.NoFileExists(t, path.Join(walDir, "code"+walExtension))
+
+		_, err = r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+
+		item, err = r.GetItem("code")
+		require.NoError(t, err)
+		require.False(t, item.Spellchecker.IsCorrect("abc"))
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		t.Parallel()
+
+		dir, walDir := t.TempDir(), t.TempDir()
+
+		r := newWALRegistry(t, dir, walDir, WithVersions(2))
+		_, err := r.Add("code", Options{Alphabet: "abc"})
+		require.NoError(t, err)
+		require.NoError(t, r.Save("code"))
+
+		old, err := r.GetItem("code")
+		require.NoError(t, err)
+		old.AddWeight(1, "abc")
+
+		require.NoError(t, r.Rollback("code", 1))
+
+		// the replaced dictionary is still used by a request
+		old.AddWeight(1, "cab")
+		old.Release()
+
+		item, err := r.GetItem("code")
+		require.NoError(t, err)
+		item.AddWeight(1, "bca")
+		item.Release()
+
+		r2 := newWALRegistry(t, dir, walDir, WithVersions(2))
+		item, err = r2.GetItem("code")
+		require.NoError(t, err)
+		require.False(t, item.Spellchecker.IsCorrect("abc"))
+		require.False(t, item.Spellchecker.IsCorrect("cab"))
+		require.True(t, item.Spellchecker.IsCorrect("bca"))
+	})
+}
+
+func Test_walLog_truncate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r := newWALRegistry(t, dir, dir)
+	_, err := r.Add("code", Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	item, err := r.GetItem("code")
+	require.NoError(t, err)
+
+	item.AddWeight(1, "abc")
+	offset, err := item.log.snapshot(func() error { return nil })
+	require.NoError(t, err)
+	item.AddWeight(1, "cab")
+
+	// the record written during the save is kept
+	require.NoError(t, item.log.truncate(offset))
+	item.AddWeight(1, "bca")
+
+	sc, err := NewRegistry(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	_, err = sc.Add("code", Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	replayItem, err := sc.GetItem("code")
+	require.NoError(t, err)
+
+	f, err := os.Open(path.Join(dir, "code"+walExtension))
+	require.NoError(t, err)
+	defer f.Close()
+
+	count, _, err := replayLog(f, replayItem)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.False(t, replayItem.Spellchecker.IsCorrect("abc"))
+	require.True(t, replayItem.Spellchecker.IsCorrect("cab"))
+	require.True(t, replayItem.Spellchecker.IsCorrect("bca"))
+}
+
+func Test_walLog_broken(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	r := newWALRegistry(t, dir, dir)
+	_, err := r.Add("code", Options{Alphabet: "abc"})
+	require.NoError(t, err)
+
+	item, err := r.GetItem("code")
+	require.NoError(t, err)
+
+	item.AddWeight(1, "abc")
+
+	// the log file could not be reopened after a truncate
+	item.log.mu.Lock()
+	require.NoError(t, item.log.file.Close())
+	item.log.broken = errors.New("reopen failed")
+	item.log.mu.Unlock()
+
+	item.AddWeight(1, "cab")
+	require.ErrorContains(t, item.log.flush(), "reopen failed")
+
+	// the save reopens the log, the saved dictionary contains the dropped record
+	require.NoError(t, r.Save("code"))
+	item.AddWeight(1, "bca")
+
+	r2 := newWALRegistry(t, dir, dir)
+	item2, err := r2.GetItem("code")
+	require.NoError(t, err)
+	require.True(t, item2.Spellchecker.IsCorrect("abc"))
+	require.True(t, item2.Spellchecker.IsCorrect("cab"))
+	require.True(t, item2.Spellchecker.IsCorrect("bca"))
+}
+
+func Test_ParseWALSync(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    WALSync
+		wantErr bool
+	}{
+		{value: "always", want: WALSyncAlways},
+		{value: "interval", want: WALSyncInterval},
+		{value: "never", want: WALSyncNever},
+		{value: "sometimes", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseWALSync(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/f1monkey/spellchecker"
+)
+
+// Words keeps the weights of the dictionary words, the spellchecker does not provide a way to iterate over them.
+// The spellchecker is rebuilt from the words on load, so its own data is not saved and the words are stored once.
+// It is nil for the dictionaries saved before the words were kept, those are saved with the spellchecker data.
+type Words struct {
+	mu     sync.Mutex
+	counts map[string]uint
+}
+
+func NewWords() *Words {
+	return &Words{
+		counts: make(map[string]uint),
+	}
+}
+
+// add increments the weights of the words the same way the spellchecker does
+func (w *Words) add(weight uint, words ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, word := range words {
+		w.counts[word] += weight
+	}
+}
+
+// Counts returns a copy of the words with their weights
+func (w *Words) Counts() map[string]uint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return maps.Clone(w.counts)
+}
+
+// spellchecker builds the spellchecker with the words
+func (w *Words) spellchecker(options Options) (*spellchecker.Spellchecker, error) {
+	result, err := spellchecker.New(options.Alphabet, spellchecker.WithMaxErrors(int(options.MaxErrors)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSpellcheckerInit, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for word, weight := range w.counts {
+		result.AddWeight(weight, word)
+	}
+
+	return result, nil
+}
+
+func (w *Words) MarshalJSON() ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return json.Marshal(w.counts)
+}
+
+func (w *Words) UnmarshalJSON(data []byte) error {
+	var counts map[string]uint
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return err
+	}
+
+	if counts == nil {
+		counts = make(map[string]uint)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.counts = counts
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Local stores the files in a directory of the local filesystem
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+func (l *Local) List(dir string) ([]File, error) {
+	entries, err := os.ReadDir(l.path(dir))
+	if os.IsNotExist(err) {
+		return []File{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	result := make([]File, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		info, err := e.Info()
+		if os.IsNotExist(err) {
+			continue // removed in the meantime
+		} else if err != nil {
+			return nil, err
+		}
+
+		result = append(result, File{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime().UTC()})
+	}
+
+	slices.SortFunc(result, func(a, b File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+func (l *Local) Open(name string) (io.ReadCloser, error) {
+	return os.Open(l.path(name))
+}
+
+// Write writes the content to a temporary file and renames it. The file and the directory are synced,
+// so the file survives a power loss once the function returns.
+func (l *Local) Write(name string, write func(w Writer) error) error {
+	filePath := l.path(name)
+	dir := filepath.Dir(filePath)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmpFile.Name()
+
+	if err := write(tmpFile); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, filePath); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+func (l *Local) Delete(name string) error {
+	err := os.Remove(l.path(name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *Local) Rename(from string, to string) error {
+	toPath := l.path(to)
+	dir := filepath.Dir(toPath)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if err := os.Rename(l.path(from), toPath); err != nil {
+		var linkErr *os.LinkError
+		if errors.As(err, &linkErr) {
+			err = &fs.PathError{Op: "rename", Path: from, Err: linkErr.Err}
+		}
+
+		return err
+	}
+
+	if fromDir := filepath.Dir(l.path(from)); fromDir != dir {
+		if err := syncDir(fromDir); err != nil {
+			return err
+		}
+	}
+
+	return syncDir(dir)
+}
+
+func (l *Local) GetMetadata() ([]byte, error) {
+	return os.ReadFile(l.path(MetadataFile))
+}
+
+func (l *Local) PutMetadata(data []byte) error {
+	return l.Write(MetadataFile, func(w Writer) error {
+		_, err := w.Write(data)
+
+		return err
+	})
+}
+
+func (l *Local) path(name string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+// syncDir makes the rename of a file in the directory durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
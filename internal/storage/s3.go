@@ -0,0 +1,377 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	s3Timeout       = 5 * time.Minute
+)
+
+// S3Config is the configuration of an S3-compatible object storage (AWS S3, MinIO etc.)
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.eu-central-1.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	Prefix    string // prefix of the object keys, e.g. "spellchecker/"
+	AccessKey string
+	SecretKey string
+}
+
+// S3 stores the files as objects of an S3 bucket. Path-style requests are used, so it works with MinIO and other
+// S3-compatible storages. Objects are replaced atomically by the storage, so several instances can share the bucket.
+type S3 struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3(config S3Config) (*S3, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q", config.Endpoint)
+	}
+
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket must be provided")
+	}
+
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	return &S3{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: s3Timeout},
+		now:      time.Now,
+	}, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (s *S3) List(dir string) ([]File, error) {
+	prefix := s.key(dir)
+	if dir != "" {
+		prefix += "/"
+	}
+
+	result := []File{}
+	token := ""
+
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, "", query, nil, 0, nil)
+		if err != nil {
+			return nil, &fs.PathError{Op: "list", Path: dir, Err: err}
+		}
+
+		var list listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, &fs.PathError{Op: "list", Path: dir, Err: err}
+		}
+
+		for _, c := range list.Contents {
+			name := strings.TrimPrefix(c.Key, prefix)
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+
+			result = append(result, File{Name: name, Size: c.Size, ModTime: c.LastModified.UTC()})
+		}
+
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			break
+		}
+
+		token = list.NextContinuationToken
+	}
+
+	slices.SortFunc(result, func(a, b File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+func (s *S3) Open(name string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, s.key(name), nil, nil, 0, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &s3Body{body: resp.Body, name: name}, nil
+}
+
+// Write buffers the content in a temporary file, the object is uploaded when the content is complete
+func (s *S3) Write(name string, write func(w Writer) error) error {
+	tmpFile, err := os.CreateTemp("", "spellchecker-s3-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	if err := write(tmpFile); err != nil {
+		return err
+	}
+
+	size, err := tmpFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	resp, err := s.do(http.MethodPut, s.key(name), nil, tmpFile, size, nil)
+	if err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+
+	return resp.Body.Close()
+}
+
+func (s *S3) Delete(name string) error {
+	resp, err := s.do(http.MethodDelete, s.key(name), nil, nil, 0, nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return &fs.PathError{Op: "delete", Path: name, Err: err}
+	}
+
+	return resp.Body.Close()
+}
+
+// Rename copies the object to the new key and deletes the old one, S3 has no renames
+func (s *S3) Rename(from string, to string) error {
+	header := http.Header{"X-Amz-Copy-Source": {uriEncode("/"+s.config.Bucket+"/"+s.key(from), false)}}
+
+	resp, err := s.do(http.MethodPut, s.key(to), nil, nil, 0, header)
+	if err != nil {
+		return &fs.PathError{Op: "rename", Path: from, Err: err}
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return err
+	}
+
+	return s.Delete(from)
+}
+
+func (s *S3) GetMetadata() ([]byte, error) {
+	rc, err := s.Open(MetadataFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+func (s *S3) PutMetadata(data []byte) error {
+	return s.Write(MetadataFile, func(w Writer) error {
+		_, err := w.Write(data)
+
+		return err
+	})
+}
+
+func (s *S3) key(name string) string {
+	return s.config.Prefix + name
+}
+
+// do sends the signed request with the additional headers. An error is returned for a non-2xx response, fs.ErrNotExist for 404.
+func (s *S3) do(method string, key string, query url.Values, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.config.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.ContentLength = size
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req.Header.Set("X-Amz-Date", s.now().UTC().Format("20060102T150405Z"))
+	sign(req, s.config)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fs.ErrNotExist
+	}
+
+	var e s3Error
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := xml.Unmarshal(data, &e); err != nil || e.Code == "" {
+		return nil, fmt.Errorf("s3: unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+
+	return nil, fmt.Errorf("s3: %s: %s", e.Code, e.Message)
+}
+
+// s3Body reports the errors of reading the object as *fs.PathError like a local file does
+type s3Body struct {
+	body io.ReadCloser
+	name string
+}
+
+func (b *s3Body) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if err != nil && err != io.EOF {
+		err = &fs.PathError{Op: "read", Path: b.name, Err: err}
+	}
+
+	return n, err
+}
+
+func (b *s3Body) Close() error {
+	return b.body.Close()
+}
+
+// sign adds the AWS Signature Version 4 to the request. The request must have the X-Amz-Date and
+// X-Amz-Content-Sha256 headers, all the headers of the request are signed.
+func sign(req *http.Request, config S3Config) {
+	amzDate := req.Header.Get("X-Amz-Date")
+	scope := amzDate[:8] + "/" + config.Region + "/s3/aws4_request"
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := slices.Sorted(maps.Keys(headers))
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+config.SecretKey), amzDate[:8])
+	key = hmacSHA256(key, config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+config.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// canonicalQuery encodes the query sorted by keys as required by the signature
+func canonicalQuery(query url.Values) string {
+	parts := make([]string, 0, len(query))
+	for _, k := range slices.Sorted(maps.Keys(query)) {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// uriEncode encodes all the bytes except the unreserved characters (and slashes unless encodeSlash is true)
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,44 @@
+// Package storage persists the files of the dictionary registry
+package storage
+
+import (
+	"io"
+	"time"
+)
+
+// MetadataFile is the name of the registry metadata file
+const MetadataFile = "metadata"
+
+// Storage is a flat namespace of files. Names are slash separated paths relative to the root of the storage.
+// Errors of file operations are *fs.PathError, a missing file is reported with fs.ErrNotExist.
+type Storage interface {
+	// List returns the files in the directory ("" - the root) sorted by name, subdirectories are skipped.
+	// A missing directory is empty.
+	List(dir string) ([]File, error)
+	// Open returns the content of the file
+	Open(name string) (io.ReadCloser, error)
+	// Write atomically replaces the file with the content written by the function.
+	// The file is not changed if the function fails.
+	Write(name string, write func(w Writer) error) error
+	// Delete removes the file, a missing file is not an error
+	Delete(name string) error
+	// Rename moves the file to the new name, the file having the new name is replaced atomically
+	Rename(from string, to string) error
+
+	// GetMetadata returns the content of the registry metadata file
+	GetMetadata() ([]byte, error)
+	// PutMetadata atomically replaces the registry metadata file
+	PutMetadata(data []byte) error
+}
+
+// Writer allows to patch the content written before (e.g. a header which depends on the rest of the file)
+type Writer interface {
+	io.Writer
+	io.WriterAt
+}
+
+type File struct {
+	Name    string // name of the file without the directory
+	Size    int64
+	ModTime time.Time
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Storage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		storage func(t *testing.T) Storage
+	}{
+		{name: "local", storage: func(t *testing.T) Storage { return NewLocal(t.TempDir()) }},
+		{name: "s3", storage: func(t *testing.T) Storage { return newTestS3(t, "prefix/") }},
+		{name: "s3 without prefix", storage: func(t *testing.T) Storage { return newTestS3(t, "") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := tt.storage(t)
+
+			list, err := s.List("")
+			require.NoError(t, err)
+			require.Empty(t, list)
+
+			_, err = s.Open("en.dict")
+			require.ErrorIs(t, err, fs.ErrNotExist)
+
+			var pathErr *fs.PathError
+			require.ErrorAs(t, err, &pathErr)
+
+			_, err = s.GetMetadata()
+			require.ErrorIs(t, err, fs.ErrNotExist)
+
+			require.NoError(t, write(s, "en.dict", "hello"))
+			require.NoError(t, write(s, "versions/en/1.dict", "v1"))
+			require.NoError(t, write(s, "a b+c.dict", "escaped"))
+			require.NoError(t, s.PutMetadata([]byte("{}")))
+
+			// header patched after writing the rest
+			require.NoError(t, s.Write("de.dict", func(w Writer) error {
+				if _, err := w.Write([]byte("xxxxx world")); err != nil {
+					return err
+				}
+
+				_, err := w.WriteAt([]byte("hello"), 0)
+
+				return err
+			}))
+
+			require.Equal(t, "hello world", read(t, s, "de.dict"))
+			require.Equal(t, "v1", read(t, s, "versions/en/1.dict"))
+			require.Equal(t, "escaped", read(t, s, "a b+c.dict"))
+
+			metadata, err := s.GetMetadata()
+			require.NoError(t, err)
+			require.Equal(t, "{}", string(metadata))
+
+			list, err = s.List("")
+			require.NoError(t, err)
+			require.Equal(t, []string{"a b+c.dict", "de.dict", "en.dict", "metadata"}, names(list))
+			require.EqualValues(t, 11, list[1].Size)
+			require.False(t, list[1].ModTime.IsZero())
+
+			list, err = s.List("versions/en")
+			require.NoError(t, err)
+			require.Equal(t, []string{"1.dict"}, names(list))
+
+			t.Run("failed write keeps the file", func(t *testing.T) {
+				err := s.Write("en.dict", func(w Writer) error {
+					_, _ = w.Write([]byte("broken"))
+
+					return errors.New("failed")
+				})
+				require.Error(t, err)
+				require.Equal(t, "hello", read(t, s, "en.dict"))
+			})
+
+			t.Run("rename", func(t *testing.T) {
+				require.NoError(t, write(s, "tmp/fr.dict", "bonjour"))
+				require.NoError(t, write(s, "fr.dict", "old"))
+
+				require.NoError(t, s.Rename("tmp/fr.dict", "fr.dict"))
+				require.Equal(t, "bonjour", read(t, s, "fr.dict"))
+
+				_, err := s.Open("tmp/fr.dict")
+				require.ErrorIs(t, err, fs.ErrNotExist)
+
+				require.NoError(t, s.Rename("fr.dict", "prev/fr.dict"))
+				require.Equal(t, "bonjour", read(t, s, "prev/fr.dict"))
+
+				err = s.Rename("missing.dict", "fr.dict")
+				require.ErrorIs(t, err, fs.ErrNotExist)
+
+				var pathErr *fs.PathError
+				require.ErrorAs(t, err, &pathErr)
+
+				require.NoError(t, s.Delete("prev/fr.dict"))
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				require.NoError(t, s.Delete("en.dict"))
+				require.NoError(t, s.Delete("en.dict"))
+
+				_, err := s.Open("en.dict")
+				require.ErrorIs(t, err, fs.ErrNotExist)
+			})
+		})
+	}
+}
+
+func write(s Storage, name string, content string) error {
+	return s.Write(name, func(w Writer) error {
+		_, err := w.Write([]byte(content))
+
+		return err
+	})
+}
+
+func read(t *testing.T, s Storage, name string) string {
+	rc, err := s.Open(name)
+	require.NoError(t, err)
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func names(files []File) []string {
+	result := make([]string, 0, len(files))
+	for _, f := range files {
+		result = append(result, f.Name)
+	}
+
+	return result
+}
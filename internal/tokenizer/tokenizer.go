@@ -0,0 +1,157 @@
+package tokenizer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	apostrophes = "'’‘"
+	hyphens     = "-‐"
+)
+
+// Token is a word found in a text. Start and End are byte offsets in the text.
+// Hyphenated words (e.g. "state-of-the-art") are returned as a single token with parts.
+type Token struct {
+	Text  string
+	Start int
+	End   int
+	Parts []Token
+}
+
+type Tokenizer struct {
+	splitter *regexp.Regexp
+	letters  string
+}
+
+func New(splitter *regexp.Regexp) *Tokenizer {
+	return &Tokenizer{
+		splitter: splitter,
+	}
+}
+
+// WithLetters returns a copy of the tokenizer treating the runes as letters when they are adjacent to a word,
+// e.g. the punctuation keys of a keyboard layout mapped to letters on another one ("vj;yj" => "можно").
+func (t *Tokenizer) WithLetters(letters []rune) *Tokenizer {
+	result := *t
+	result.letters = string(letters)
+
+	return &result
+}
+
+// Tokens splits the text into tokens using the splitter regexp and applies the following rules:
+//   - leading and trailing apostrophes are stripped ("'weapon'" => "weapon"), inner ones are kept ("don't");
+//   - words joined with a single hyphen are grouped into one token.
+func (t *Tokenizer) Tokens(text string) []Token {
+	matches := t.splitter.FindAllStringIndex(t.mask(text), -1)
+	result := make([]Token, 0, len(matches))
+
+	for _, match := range matches {
+		token, ok := t.trim(text, match[0], match[1])
+		if !ok {
+			continue
+		}
+
+		if len(result) > 0 && isHyphen(text[result[len(result)-1].End:token.Start]) {
+			last := &result[len(result)-1]
+			if len(last.Parts) == 0 {
+				last.Parts = []Token{*last}
+			}
+
+			last.Parts = append(last.Parts, token)
+			last.End = token.End
+			last.Text = text[last.Start:last.End]
+
+			continue
+		}
+
+		result = append(result, token)
+	}
+
+	return result
+}
+
+// Words returns all the words of the text, hyphenated words are returned both as a whole and part-wise.
+func (t *Tokenizer) Words(text string) []string {
+	tokens := t.Tokens(text)
+	result := make([]string, 0, len(tokens))
+
+	for _, token := range tokens {
+		result = append(result, token.Text)
+
+		for _, p := range token.Parts {
+			result = append(result, p.Text)
+		}
+	}
+
+	return result
+}
+
+// mask replaces the extra letters adjacent to a word with letters of the same byte length,
+// so the splitter matches them while the offsets stay valid for the original text
+func (t *Tokenizer) mask(text string) string {
+	if t.letters == "" || !strings.ContainsAny(text, t.letters) {
+		return text
+	}
+
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		end := start
+		word := false
+		for ; end < len(runes) && (unicode.IsLetter(runes[end]) || strings.ContainsRune(t.letters, runes[end])); end++ {
+			word = word || unicode.IsLetter(runes[end])
+		}
+
+		if word {
+			for i := start; i < end; i++ {
+				if !unicode.IsLetter(runes[i]) {
+					runes[i] = placeholders[utf8.RuneLen(runes[i])-1]
+				}
+			}
+		}
+
+		start = max(end, start+1)
+	}
+
+	return string(runes)
+}
+
+// placeholders are letters by their UTF-8 length
+var placeholders = [utf8.UTFMax]rune{'a', 'é', 'ḁ', '𝒶'}
+
+// trim strips the leading and trailing apostrophes unless they are extra letters
+func (t *Tokenizer) trim(text string, start int, end int) (Token, bool) {
+	for start < end {
+		r, size := utf8.DecodeRuneInString(text[start:end])
+		if !t.isApostrophe(r) {
+			break
+		}
+		start += size
+	}
+
+	for start < end {
+		r, size := utf8.DecodeLastRuneInString(text[start:end])
+		if !t.isApostrophe(r) {
+			break
+		}
+		end -= size
+	}
+
+	if start == end {
+		return Token{}, false
+	}
+
+	return Token{Text: text[start:end], Start: start, End: end}, true
+}
+
+func (t *Tokenizer) isApostrophe(r rune) bool {
+	return strings.ContainsRune(apostrophes, r) && !strings.ContainsRune(t.letters, r)
+}
+
+func isHyphen(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+
+	return size > 0 && size == len(s) && strings.ContainsRune(hyphens, r)
+}
@@ -0,0 +1,161 @@
+package tokenizer
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tokenizer_Tokens(t *testing.T) {
+	t.Parallel()
+
+	tok := New(regexp.MustCompile(`['\pL]+`))
+
+	tests := []struct {
+		name  string
+		input string
+		want  []Token
+	}{
+		{
+			name:  "simple words",
+			input: "hello world",
+			want: []Token{
+				{Text: "hello", Start: 0, End: 5},
+				{Text: "world", Start: 6, End: 11},
+			},
+		},
+		{
+			name:  "quoted word",
+			input: "his 'weapon'",
+			want: []Token{
+				{Text: "his", Start: 0, End: 3},
+				{Text: "weapon", Start: 5, End: 11},
+			},
+		},
+		{
+			name:  "inner apostrophe",
+			input: "don't",
+			want: []Token{
+				{Text: "don't", Start: 0, End: 5},
+			},
+		},
+		{
+			name:  "apostrophe only",
+			input: "' a",
+			want: []Token{
+				{Text: "a", Start: 2, End: 3},
+			},
+		},
+		{
+			name:  "hyphenated word",
+			input: "a state-of-the-art tool",
+			want: []Token{
+				{Text: "a", Start: 0, End: 1},
+				{
+					Text: "state-of-the-art", Start: 2, End: 18,
+					Parts: []Token{
+						{Text: "state", Start: 2, End: 7},
+						{Text: "of", Start: 8, End: 10},
+						{Text: "the", Start: 11, End: 14},
+						{Text: "art", Start: 15, End: 18},
+					},
+				},
+				{Text: "tool", Start: 19, End: 23},
+			},
+		},
+		{
+			name:  "quoted hyphenated word",
+			input: "'well-known'",
+			want: []Token{
+				{
+					Text: "well-known", Start: 1, End: 11,
+					Parts: []Token{
+						{Text: "well", Start: 1, End: 5},
+						{Text: "known", Start: 6, End: 11},
+					},
+				},
+			},
+		},
+		{
+			name:  "dash separated words",
+			input: "one - two",
+			want: []Token{
+				{Text: "one", Start: 0, End: 3},
+				{Text: "two", Start: 6, End: 9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tok.Tokens(tt.input))
+		})
+	}
+}
+
+func Test_Tokenizer_WithLetters(t *testing.T) {
+	t.Parallel()
+
+	base := New(regexp.MustCompile(`['\pL]+`))
+	tok := base.WithLetters([]rune(";',."))
+
+	tests := []struct {
+		name  string
+		input string
+		want  []Token
+	}{
+		{
+			name:  "inner letter",
+			input: "vj;yj",
+			want:  []Token{{Text: "vj;yj", Start: 0, End: 5}},
+		},
+		{
+			name:  "leading and trailing letters",
+			input: ";tyf, lheu.",
+			want: []Token{
+				{Text: ";tyf,", Start: 0, End: 5},
+				{Text: "lheu.", Start: 6, End: 11},
+			},
+		},
+		{
+			name:  "apostrophes are kept",
+			input: "'nj",
+			want:  []Token{{Text: "'nj", Start: 0, End: 3}},
+		},
+		{
+			name:  "punctuation alone",
+			input: "a ; b",
+			want: []Token{
+				{Text: "a", Start: 0, End: 1},
+				{Text: "b", Start: 4, End: 5},
+			},
+		},
+		{
+			name:  "multibyte text",
+			input: "слово;",
+			want:  []Token{{Text: "слово;", Start: 0, End: 11}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tok.Tokens(tt.input))
+		})
+	}
+
+	// the original tokenizer is not changed
+	require.Equal(t, []string{"vj", "yj"}, base.Words("vj;yj"))
+}
+
+func Test_Tokenizer_Words(t *testing.T) {
+	t.Parallel()
+
+	tok := New(regexp.MustCompile(`['\pL]+`))
+
+	require.Equal(t, []string{"a", "well-known", "well", "known", "word"}, tok.Words("a well-known 'word'"))
+}
@@ -11,14 +11,14 @@ import (
 	swgui "github.com/swaggest/swgui/v5emb"
 )
 
-func NewServer(appCtx context.Context, registry *spellchecker.Registry, splitter *regexp.Regexp) *web.Service {
+func NewServer(appCtx context.Context, registry *spellchecker.Registry, splitter *regexp.Regexp, opts ...routes.Option) *web.Service {
 	s := web.NewService(openapi31.NewReflector())
 
 	s.OpenAPISchema().SetTitle("Spellchecker")
 	s.OpenAPISchema().SetDescription("To fix words")
 	s.OpenAPISchema().SetVersion("v1")
 
-	s.Route("/v1", routes.Routes(registry, splitter))
+	s.Route("/v1", routes.Routes(registry, splitter, opts...))
 
 	// Swagger UI endpoint at /docs.
 	s.Docs("/docs", swgui.New)